@@ -39,6 +39,9 @@ type GameBoard struct {
 	transposed  bool
 	tilesPlayed int
 	lastCopy    *GameBoard
+	// hash is the Zobrist hash of the tiles; see Hash.
+	hash    uint64
+	zobrist [][]uint64
 }
 
 // MakeBoard creates a board from a description string.
@@ -52,7 +55,7 @@ func MakeBoard(desc []string) *GameBoard {
 		}
 		rows = append(rows, row)
 	}
-	g := &GameBoard{squares: rows}
+	g := &GameBoard{squares: rows, zobrist: zobristTable(len(rows))}
 	// Call Clear to set all crosses.
 	g.Clear()
 	return g
@@ -76,7 +79,7 @@ func (g *GameBoard) GetSquare(row int, col int) *Square {
 }
 
 func (g *GameBoard) SetLetter(row int, col int, letter alphabet.MachineLetter) {
-	g.squares[row][col].letter = letter
+	g.setLetter(row, col, letter)
 }
 
 func (g *GameBoard) GetLetter(row int, col int) alphabet.MachineLetter {
@@ -151,6 +154,7 @@ func (g *GameBoard) Clear() {
 		}
 	}
 	g.tilesPlayed = 0
+	g.hash = 0
 	// We set all crosses because every letter is technically allowed
 	// on every cross-set at the very beginning.
 	g.SetAllCrosses()
@@ -322,7 +326,7 @@ func (g *GameBoard) PlaceMoveTiles(m *move.Move) {
 			col = colStart + idx
 			row = rowStart
 		}
-		g.squares[row][col].letter = tile
+		g.setLetter(row, col, tile)
 	}
 }
 
@@ -340,7 +344,7 @@ func (g *GameBoard) UnplaceMoveTiles(m *move.Move) {
 			col = colStart + idx
 			row = rowStart
 		}
-		g.squares[row][col].letter = alphabet.EmptySquareMarker
+		g.setLetter(row, col, alphabet.EmptySquareMarker)
 	}
 }
 
@@ -598,6 +602,8 @@ func (g *GameBoard) Copy() *GameBoard {
 	newg.squares = squares
 	newg.transposed = g.transposed
 	newg.tilesPlayed = g.tilesPlayed
+	newg.hash = g.hash
+	newg.zobrist = g.zobrist
 	// newg.playHistory = append([]string{}, g.playHistory...)
 	return newg
 }
@@ -620,6 +626,7 @@ func (g *GameBoard) CopyFrom(b *GameBoard) {
 	}
 	g.transposed = b.transposed
 	g.tilesPlayed = b.tilesPlayed
+	g.hash = b.hash
 }
 
 func (g *GameBoard) GetTilesPlayed() int {
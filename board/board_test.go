@@ -148,3 +148,46 @@ func TestFormedWordsBlank(t *testing.T) {
 	}
 	is.Equal(uvWords, []string{"TAEL", "TA", "AN", "RESPONDED", "LO"})
 }
+
+func TestHash(t *testing.T) {
+	is := is.New(t)
+	alph := alphabet.EnglishAlphabet()
+
+	b := MakeBoard(CrosswordGameBoard)
+	is.Equal(b.Hash(), uint64(0))
+	m := move.NewScoringMoveSimple(12, "8D", "WInDY", "", alph)
+	b.PlaceMoveTiles(m)
+	h := b.Hash()
+	is.True(h != 0)
+
+	// The same tiles placed on a transposed board hash the same; H4 down
+	// on the transposed board is 8D across.
+	c := MakeBoard(CrosswordGameBoard)
+	c.Transpose()
+	c.PlaceMoveTiles(move.NewScoringMoveSimple(12, "H4", "WInDY", "", alph))
+	is.Equal(c.Hash(), h)
+	c.Transpose()
+	is.Equal(c.Hash(), h)
+
+	// A blank is not the letter it stands for.
+	d := MakeBoard(CrosswordGameBoard)
+	d.PlaceMoveTiles(move.NewScoringMoveSimple(12, "8D", "WINDY", "", alph))
+	is.True(d.Hash() != h)
+
+	b.SaveCopy()
+	through := move.NewScoringMoveSimple(10, "F6", "OW.", "", alph)
+	b.PlaceMoveTiles(through)
+	is.True(b.Hash() != h)
+	b.UnplaceMoveTiles(through)
+	is.Equal(b.Hash(), h)
+	b.PlaceMoveTiles(through)
+	b.RestoreFromCopy()
+	is.Equal(b.Hash(), h)
+	is.Equal(b.Copy().Hash(), h)
+
+	b.UnplaceMoveTiles(m)
+	is.Equal(b.Hash(), uint64(0))
+	b.PlaceMoveTiles(m)
+	b.Clear()
+	is.Equal(b.Hash(), uint64(0))
+}
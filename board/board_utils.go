@@ -65,9 +65,9 @@ func (g *GameBoard) setFromPlaintext(qText string,
 			if err != nil {
 				// Ignore the error; we are passing in a space or another
 				// board marker.
-				g.squares[i][j/2].letter = alphabet.EmptySquareMarker
+				g.setLetter(i, j/2, alphabet.EmptySquareMarker)
 			} else {
-				g.squares[i][j/2].letter = letter
+				g.setLetter(i, j/2, letter)
 				g.tilesPlayed++
 				playedTiles = append(playedTiles, letter)
 			}
@@ -103,7 +103,7 @@ func (b *GameBoard) SetRow(rowNum int, letters string, alph *alphabet.Alphabet)
 	for idx := 0; idx < b.Dim(); idx++ {
 		b.SetLetter(int(rowNum), idx, alphabet.EmptySquareMarker)
 	}
-	for idx, r := range letters {
+	for idx, r := range []rune(letters) {
 		if r != ' ' {
 			letter, err := alph.Val(r)
 			if err != nil {
@@ -137,3 +137,40 @@ func (g *GameBoard) Equals(g2 *GameBoard) bool {
 
 	return true
 }
+
+// SetRows clears the board and sets its rows from the top, with SetRow.
+// Rows that are left out, and the ends of short rows, are empty. It
+// returns the tiles put on the board. Anchors and cross-sets are not
+// updated.
+func (g *GameBoard) SetRows(rows []string, alph *alphabet.Alphabet) (alphabet.MachineWord, error) {
+	if len(rows) > g.Dim() {
+		return nil, fmt.Errorf("the board has %d rows, not %d", g.Dim(), len(rows))
+	}
+	tiles := alphabet.MachineWord{}
+	for r, row := range rows {
+		if len([]rune(row)) > g.Dim() {
+			return nil, fmt.Errorf("row %d is longer than the board", r+1)
+		}
+		for _, ch := range row {
+			if ch == ' ' {
+				continue
+			}
+			ml, err := alph.Val(ch)
+			if err != nil || !isTile(ml) {
+				return nil, fmt.Errorf("row %d has %q, which is not a tile", r+1, ch)
+			}
+			tiles = append(tiles, ml)
+		}
+	}
+	g.Clear()
+	for r, row := range rows {
+		g.SetRow(r, row, alph)
+	}
+	return tiles, nil
+}
+
+// isTile returns true if ml is a letter tile or a designated blank.
+func isTile(ml alphabet.MachineLetter) bool {
+	return ml != alphabet.BlankMachineLetter && ml != alphabet.PlayedThroughMarker &&
+		ml != alphabet.SeparationMachineLetter && ml != alphabet.EmptySquareMarker
+}
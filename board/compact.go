@@ -0,0 +1,85 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/domino14/cwgame/alphabet"
+)
+
+// CompactString returns the board on one line. Rows are separated by
+// slashes, a number stands for that many empty squares, and blanks are
+// in lower case, as in "15/15/.../7WINDY3/...".
+func (g *GameBoard) CompactString(alph *alphabet.Alphabet) string {
+	lines := []string{}
+	for r := 0; r < g.Dim(); r++ {
+		var sb strings.Builder
+		empty := 0
+		for c := 0; c < g.Dim(); c++ {
+			ml := g.GetLetter(r, c)
+			if ml == alphabet.EmptySquareMarker {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteRune(ml.UserVisible(alph))
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, "/")
+}
+
+// ExpandCompact turns a board written by CompactString into a string for
+// each row, in the form used by SetRow, for a board with dim rows and
+// columns.
+func ExpandCompact(s string, dim int) ([]string, error) {
+	lines := strings.Split(s, "/")
+	if len(lines) != dim {
+		return nil, fmt.Errorf("the board has %d rows, not %d", dim, len(lines))
+	}
+	rows := make([]string, dim)
+	for r, line := range lines {
+		var row []rune
+		runes := []rune(line)
+		for i := 0; i < len(runes); i++ {
+			if !unicode.IsDigit(runes[i]) {
+				row = append(row, runes[i])
+				continue
+			}
+			j := i
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			n, err := strconv.Atoi(string(runes[i:j]))
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("row %d has a bad number of empty squares", r+1)
+			}
+			row = append(row, []rune(strings.Repeat(" ", n))...)
+			i = j - 1
+		}
+		if len(row) != dim {
+			return nil, fmt.Errorf("row %d has %d squares, not %d", r+1, len(row), dim)
+		}
+		rows[r] = string(row)
+	}
+	return rows, nil
+}
+
+// SetFromCompact clears the board and puts the tiles of a board written
+// by CompactString on it. It returns the tiles, so that they can be taken
+// out of the bag. Anchors and cross-sets are not updated.
+func (g *GameBoard) SetFromCompact(s string, alph *alphabet.Alphabet) (alphabet.MachineWord, error) {
+	rows, err := ExpandCompact(s, g.Dim())
+	if err != nil {
+		return nil, err
+	}
+	return g.SetRows(rows, alph)
+}
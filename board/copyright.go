@@ -4,6 +4,10 @@ var (
 	// CrosswordGameBoard is a board for a fun Crossword Game, featuring lots
 	// of wingos and blonks.
 	CrosswordGameBoard []string
+
+	// Layouts are the board layouts that can be referred to by name, for
+	// example in a GCG.
+	Layouts map[string][]string
 )
 
 func init() {
@@ -24,4 +28,7 @@ func init() {
 		` -   "   "   - `,
 		`=  '   =   '  =`,
 	}
+	Layouts = map[string][]string{
+		"CrosswordGame": CrosswordGameBoard,
+	}
 }
@@ -0,0 +1,65 @@
+package board
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/domino14/cwgame/alphabet"
+)
+
+// zobristSeed seeds the random keys, so that hashes are the same from
+// run to run and can be stored, for example in an opening book.
+const zobristSeed = 0x5eed
+
+// zobristLetters is the number of machine letters with a key; blanked
+// letters go up to BlankOffset + MaxAlphabetSize.
+const zobristLetters = alphabet.BlankOffset + alphabet.MaxAlphabetSize
+
+var (
+	zobristMu   sync.Mutex
+	zobristKeys = map[int][][]uint64{}
+)
+
+// zobristTable returns the keys for boards of dimension dim, one for each
+// square and letter. Empty squares have no key.
+func zobristTable(dim int) [][]uint64 {
+	zobristMu.Lock()
+	defer zobristMu.Unlock()
+	if keys, ok := zobristKeys[dim]; ok {
+		return keys
+	}
+	r := rand.New(rand.NewSource(zobristSeed + int64(dim)))
+	keys := make([][]uint64, dim*dim)
+	for i := range keys {
+		keys[i] = make([]uint64, zobristLetters)
+		for j := range keys[i] {
+			keys[i][j] = r.Uint64()
+		}
+	}
+	zobristKeys[dim] = keys
+	return keys
+}
+
+// Hash returns a 64-bit Zobrist hash of the tiles on the board. Boards of
+// the same dimension with the same tiles have the same hash, whether they
+// are transposed or not. It is kept up to date as tiles are placed and
+// removed, so it is cheap to call.
+func (g *GameBoard) Hash() uint64 {
+	return g.hash
+}
+
+// setLetter puts letter on a square and updates the hash.
+func (g *GameBoard) setLetter(row, col int, letter alphabet.MachineLetter) {
+	sq := g.squares[row][col]
+	if g.transposed {
+		row, col = col, row
+	}
+	idx := row*g.Dim() + col
+	if sq.letter != alphabet.EmptySquareMarker {
+		g.hash ^= g.zobrist[idx][sq.letter]
+	}
+	if letter != alphabet.EmptySquareMarker {
+		g.hash ^= g.zobrist[idx][letter]
+	}
+	sq.letter = letter
+}
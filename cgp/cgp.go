@@ -0,0 +1,176 @@
+// Package cgp reads and writes positions in a compact, one-line notation,
+// so that they can be put in URLs, chat messages, test fixtures and
+// puzzle databases. A position looks like
+//
+//	15/15/15/15/15/15/15/3WInDY7/15/15/15/15/15/15/15 AEIRST?/ 0/32 0 lex NWL20;
+//
+// The fields are the board, as written by board.GameBoard.CompactString,
+// the two racks, the two scores and the number of scoreless turns in a
+// row. The rack and score of the player on turn come first; a rack that
+// isn't known is left empty. They can be followed by operations, each
+// a name, a value and a semicolon:
+//
+//	lex  the lexicon
+//	ld   the letter distribution
+//	bdn  the board layout, as named in board.Layouts
+//	var  the variant
+//
+// Operations with other names are kept, but not used.
+package cgp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+)
+
+// The operations that are understood.
+const (
+	OpLexicon            = "lex"
+	OpLetterDistribution = "ld"
+	OpBoardLayout        = "bdn"
+	OpVariant            = "var"
+)
+
+// Position is a position in the notation.
+type Position struct {
+	Board string
+	// Racks and Scores have those of the player on turn first.
+	Racks          [2]string
+	Scores         [2]int
+	ScorelessTurns int
+	Operations     map[string]string
+}
+
+// Parse reads a position.
+func Parse(s string) (*Position, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 4 {
+		return nil, errors.New("a position needs a board, racks, scores and scoreless turns")
+	}
+	p := &Position{Board: fields[0], Operations: map[string]string{}}
+	racks := strings.Split(fields[1], "/")
+	if len(racks) != 2 {
+		return nil, fmt.Errorf("%v is not two racks separated by a slash", fields[1])
+	}
+	copy(p.Racks[:], racks)
+	scores := strings.Split(fields[2], "/")
+	if len(scores) != 2 {
+		return nil, fmt.Errorf("%v is not two scores separated by a slash", fields[2])
+	}
+	for i, score := range scores {
+		n, err := strconv.Atoi(score)
+		if err != nil {
+			return nil, fmt.Errorf("bad score %v", score)
+		}
+		p.Scores[i] = n
+	}
+	n, err := strconv.Atoi(fields[3])
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("bad number of scoreless turns %v", fields[3])
+	}
+	p.ScorelessTurns = n
+
+	ops := strings.TrimSpace(strings.Join(fields[4:], " "))
+	if ops != "" && !strings.HasSuffix(ops, ";") {
+		return nil, errors.New("every operation must end with a semicolon")
+	}
+	for _, op := range strings.Split(ops, ";") {
+		op = strings.TrimSpace(op)
+		if op == "" {
+			continue
+		}
+		parts := strings.SplitN(op, " ", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("operation %v has no value", op)
+		}
+		p.Operations[parts[0]] = strings.TrimSpace(parts[1])
+	}
+	return p, nil
+}
+
+// String writes the position, with the operations sorted by name.
+func (p *Position) String() string {
+	s := fmt.Sprintf("%v %v/%v %d/%d %d", p.Board, p.Racks[0], p.Racks[1],
+		p.Scores[0], p.Scores[1], p.ScorelessTurns)
+	names := []string{}
+	for name := range p.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s += fmt.Sprintf(" %v %v;", name, p.Operations[name])
+	}
+	return s
+}
+
+// FromGame returns the current position of g.
+func FromGame(g *game.Game) *Position {
+	onturn := g.PlayerOnTurn()
+	p := &Position{Board: g.CompactBoard(), ScorelessTurns: g.ScorelessTurns(),
+		Operations: map[string]string{}}
+	for i, player := range []int{onturn, 1 - onturn} {
+		p.Racks[i] = g.RackLettersFor(player)
+		p.Scores[i] = g.PointsFor(player)
+	}
+	h := g.History()
+	if h.Lexicon != "" && h.Lexicon != (lexicon.AcceptAll{}).Name() {
+		p.Operations[OpLexicon] = h.Lexicon
+	}
+	for name, value := range map[string]string{
+		OpLetterDistribution: h.LetterDistribution,
+		OpBoardLayout:        h.BoardLayout,
+		OpVariant:            h.Variant,
+	} {
+		if value != "" {
+			p.Operations[name] = value
+		}
+	}
+	return p
+}
+
+// history returns a history with no events and the lexicon and variant
+// of the position.
+func (p *Position) history() *pb.GameHistory {
+	return &pb.GameHistory{
+		Lexicon:            p.Operations[OpLexicon],
+		LetterDistribution: p.Operations[OpLetterDistribution],
+		BoardLayout:        p.Operations[OpBoardLayout],
+		Variant:            p.Operations[OpVariant],
+	}
+}
+
+// Rules returns the basic rules for the board layout and letter
+// distribution of the position.
+func (p *Position) Rules(cfg *config.Config) (*game.GameRules, error) {
+	boardLayout, letterDistributionName := game.HistoryToVariant(p.history())
+	return game.NewBasicGameRules(cfg, boardLayout, letterDistributionName)
+}
+
+// NewGame creates a game at the position. The first player is on turn.
+func (p *Position) NewGame(rules *game.GameRules, players []*pb.PlayerInfo) (*game.Game, error) {
+	g, err := game.NewFromPosition(rules, players, &game.Position{
+		Board:          p.Board,
+		Racks:          p.Racks,
+		Scores:         p.Scores,
+		ScorelessTurns: p.ScorelessTurns,
+	})
+	if err != nil {
+		return nil, err
+	}
+	h, from := g.History(), p.history()
+	if from.Lexicon != "" {
+		h.Lexicon = from.Lexicon
+	}
+	h.LetterDistribution = from.LetterDistribution
+	h.BoardLayout = from.BoardLayout
+	h.Variant = from.Variant
+	return g, nil
+}
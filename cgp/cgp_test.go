@@ -0,0 +1,78 @@
+package cgp
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+var players = []*pb.PlayerInfo{
+	{Nickname: "doug", UserId: "doug"},
+	{Nickname: "emely", UserId: "emely"},
+}
+
+func TestRoundTrip(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	// emely to play ?FS against doug's EGOP.
+	orig, err := game.NewFromHistory(h, rules, 26)
+	is.NoErr(err)
+
+	p := FromGame(orig)
+	is.Equal(p.Racks, [2]string{"FS?", "EGOP"})
+	is.Equal(p.Scores, [2]int{310, 451})
+	s := p.String()
+
+	parsed, err := Parse(s)
+	is.NoErr(err)
+	is.Equal(parsed, p)
+	is.Equal(parsed.String(), s)
+
+	rules, err = parsed.Rules(&DefaultConfig)
+	is.NoErr(err)
+	g, err := parsed.NewGame(rules, []*pb.PlayerInfo{players[1], players[0]})
+	is.NoErr(err)
+	is.True(g.Board().Equals(orig.Board()))
+	is.Equal(g.Bag().TilesRemaining(), 0)
+	is.Equal(g.SpreadFor(0), -141)
+	is.Equal(FromGame(g).String(), s)
+}
+
+func TestParse(t *testing.T) {
+	is := is.New(t)
+	p, err := Parse("15/15/15/15/15/15/15/3WInDY7/15/15/15/15/15/15/15 AEIRST?/ 0/32 1 lex NWL20; bdn SuperCrosswordGame;")
+	is.NoErr(err)
+	is.Equal(p.Racks, [2]string{"AEIRST?", ""})
+	is.Equal(p.Scores, [2]int{0, 32})
+	is.Equal(p.ScorelessTurns, 1)
+	is.Equal(p.Operations, map[string]string{
+		OpLexicon:     "NWL20",
+		OpBoardLayout: "SuperCrosswordGame",
+	})
+	is.Equal(p.String(), "15/15/15/15/15/15/15/3WInDY7/15/15/15/15/15/15/15 AEIRST?/ 0/32 1 bdn SuperCrosswordGame; lex NWL20;")
+
+	for _, s := range []string{
+		"",
+		"15/15 A/B 0/0",
+		"15/15 AB 0/0 0",
+		"15/15 A/B 0 0",
+		"15/15 A/B 0/x 0",
+		"15/15 A/B 0/0 -1",
+		"15/15 A/B 0/0 0 lex NWL20",
+		"15/15 A/B 0/0 0 lex;",
+	} {
+		_, err := Parse(s)
+		is.True(err != nil)
+	}
+}
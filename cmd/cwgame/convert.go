@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/htmlio"
+)
+
+// The formats a game history can be read from or written to.
+const (
+	formatGCG  = "gcg"
+	formatJSON = "json"
+	formatPB   = "pb"
+	// formatImport can only be read; the format is detected from the
+	// contents, which may also be a log from another site.
+	formatImport = "import"
+	// formatHTML can only be written.
+	formatHTML = "html"
+)
+
+// formatFromFilename guesses the format of a file from its extension.
+func formatFromFilename(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gcg":
+		return formatGCG, nil
+	case ".json":
+		return formatJSON, nil
+	case ".pb", ".bin":
+		return formatPB, nil
+	case ".html", ".htm":
+		return formatHTML, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %v; please specify it", filename)
+}
+
+// loadHistory loads a game history in any of the supported formats. If
+// format is empty it is determined from the filename, or else from the
+// contents.
+func loadHistory(cfg *config.Config, filename string, format string) (*pb.GameHistory, error) {
+	var err error
+	if format == "" {
+		format, err = formatFromFilename(filename)
+		if err != nil {
+			format = formatImport
+		}
+	}
+	if format == formatGCG {
+		return gcgio.ParseGCG(cfg, filename)
+	}
+	if format == formatImport {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return gcgio.Import(cfg, f)
+	}
+	bts, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	h := &pb.GameHistory{}
+	switch format {
+	case formatJSON:
+		err = protojson.Unmarshal(bts, h)
+	case formatPB:
+		err = proto.Unmarshal(bts, h)
+	default:
+		err = fmt.Errorf("unsupported format %v", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// serializeHistory writes out a history in the given format.
+func serializeHistory(cfg *config.Config, h *pb.GameHistory, format string) ([]byte, error) {
+	switch format {
+	case formatGCG:
+		gcg, err := gcgio.GameHistoryToGCG(h, true)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(gcg), nil
+	case formatJSON:
+		bts, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true}.Marshal(h)
+		if err != nil {
+			return nil, err
+		}
+		return append(bts, '\n'), nil
+	case formatPB:
+		return proto.Marshal(h)
+	case formatHTML:
+		rules, err := rulesFor(cfg, h)
+		if err != nil {
+			return nil, err
+		}
+		var html bytes.Buffer
+		err = htmlio.GameHistoryToHTML(&html, h, rules)
+		if err != nil {
+			return nil, err
+		}
+		return html.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported format %v", format)
+}
+
+func runConvert(cfg *config.Config, args []string) error {
+	fs, setup := newFlagSet("convert", cfg)
+	from := fs.String("from", "", "input format (gcg, json, pb or import); guessed from the extension if not given")
+	to := fs.String("to", "", "output format (gcg, json, pb or html); guessed from the output filename if not given")
+	out := fs.String("o", "", "output file; standard output if not given")
+	fs.Parse(args)
+	setup()
+	if fs.NArg() != 1 {
+		return errors.New("expected exactly one input file")
+	}
+
+	format := *to
+	if format == "" {
+		if *out == "" {
+			return errors.New("need an output format (-to) when writing to standard output")
+		}
+		var err error
+		format, err = formatFromFilename(*out)
+		if err != nil {
+			return err
+		}
+	}
+
+	h, err := loadHistory(cfg, fs.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+	bts, err := serializeHistory(cfg, h, format)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(bts)
+		return err
+	}
+	return ioutil.WriteFile(*out, bts, 0644)
+}
@@ -0,0 +1,89 @@
+// Command cwgame is a command-line tool for inspecting, validating,
+// converting and annotating Crossword Game records.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(cfg *config.Config, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"replay", "show the board after every turn of a game", runReplay},
+		{"validate", "check a game for score, rack and word errors", runValidate},
+		{"convert", "convert between GCG, JSON and protobuf game histories", runConvert},
+		{"shell", "play and annotate games interactively", runShell},
+		{"winpct", "rebuild the win percentage table from self-play games", runWinPct},
+		{"openings", "build an opening book from directories of games", runOpenings},
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: cwgame <command> [flags] [args]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10v %v\n", c.name, c.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun cwgame <command> -h for the flags of each command.\n")
+}
+
+// newFlagSet creates a flag set with the flags that every command shares.
+// The returned function must be called after parsing the flags.
+func newFlagSet(name string, cfg *config.Config) (*flag.FlagSet, func()) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&cfg.LetterDistributionPath, "ld-path", cfg.LetterDistributionPath,
+		"directory with letter distribution files")
+	fs.StringVar(&cfg.DefaultLexicon, "default-lexicon", cfg.DefaultLexicon,
+		"lexicon to assume if a game does not specify one")
+	fs.BoolVar(&cfg.Debug, "debug", cfg.Debug, "show debug logs")
+	return fs, func() {
+		if cfg.Debug {
+			zerolog.SetGlobalLevel(zerolog.DebugLevel)
+		}
+	}
+}
+
+// rulesFor creates the basic rules needed to replay the given history.
+func rulesFor(cfg *config.Config, h *pb.GameHistory) (*game.GameRules, error) {
+	if h.Lexicon == "" {
+		h = &pb.GameHistory{Lexicon: cfg.DefaultLexicon, Variant: h.Variant,
+			BoardLayout: h.BoardLayout, LetterDistribution: h.LetterDistribution}
+	}
+	boardLayout, letterDistributionName := game.HistoryToVariant(h)
+	return game.NewBasicGameRules(cfg, boardLayout, letterDistributionName)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	cfg := config.DefaultConfig()
+
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(&cfg, os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "cwgame %v: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
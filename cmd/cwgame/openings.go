@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/montecarlo"
+	"github.com/domino14/cwgame/movegen"
+	"github.com/domino14/cwgame/openings"
+)
+
+// runOpenings builds an opening book from directories of GCG files.
+func runOpenings(cfg *config.Config, args []string) error {
+	fs, setup := newFlagSet("openings", cfg)
+	wordList := fs.String("lexicon", "", "word list file (one word per line) to find more openings with; none are found if empty")
+	dist := fs.String("letter-distribution", "English", "letter distribution of the games")
+	candidates := fs.Int("candidates", openings.DefaultCandidates, "number of found openings to add for each rack")
+	n := fs.Int("n", 10, "number of openings to keep for each rack; all if 0")
+	iterations := fs.Int("sim-iterations", 0, "rank openings by simulating them this many times instead of by static equity")
+	out := fs.String("out", "openings.json", "file to write the book to")
+	fs.Parse(args)
+	setup()
+	if fs.NArg() == 0 {
+		return errors.New("no directories of games given")
+	}
+	if *iterations > 0 && *wordList == "" {
+		return errors.New("a word list is needed to simulate with")
+	}
+
+	rules, err := game.NewBasicGameRules(cfg, board.CrosswordGameBoard, *dist)
+	if err != nil {
+		return err
+	}
+	ld := rules.LetterDistribution()
+	calc := equity.NewLeaveCalculator(ld)
+	var gen movegen.MoveGenerator
+	if *wordList != "" {
+		wl, err := lexicon.LoadWordListFile(*wordList, ld.Alphabet())
+		if err != nil {
+			return err
+		}
+		gen = movegen.NewTrieGenerator(wl, ld)
+	}
+	b := openings.NewBuilder(rules, gen, calc)
+	b.Candidates = *candidates
+	if *iterations > 0 {
+		b.Sim = montecarlo.NewSimmer(gen, calc)
+		b.Stop = montecarlo.StopCondition{Iterations: *iterations}
+	}
+
+	games := 0
+	for _, dir := range fs.Args() {
+		added, err := b.AddDir(dir)
+		if err != nil {
+			return err
+		}
+		games += added
+	}
+	bk, err := b.Build(context.Background(), *n)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := bk.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d racks from %d games to %v\n", len(bk.Racks), games, *out)
+	return nil
+}
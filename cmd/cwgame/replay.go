@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+)
+
+func runReplay(cfg *config.Config, args []string) error {
+	fs, setup := newFlagSet("replay", cfg)
+	format := fs.String("format", "", "input format (gcg, json, pb or import); guessed from the extension if not given")
+	turn := fs.Int("turn", -1, "only show the position at this turn")
+	step := fs.Bool("step", false, "wait for Enter between turns")
+	fs.Parse(args)
+	setup()
+	if fs.NArg() != 1 {
+		return errors.New("expected exactly one game file")
+	}
+
+	h, err := loadHistory(cfg, fs.Arg(0), *format)
+	if err != nil {
+		return err
+	}
+	rules, err := rulesFor(cfg, h)
+	if err != nil {
+		return err
+	}
+	g, err := game.ReplayHistory(h, rules, 0)
+	if err != nil {
+		return err
+	}
+
+	first, last := 0, len(h.Events)
+	if *turn >= 0 {
+		first, last = *turn, *turn
+	}
+	stdin := bufio.NewReader(os.Stdin)
+	for t := first; t <= last; t++ {
+		err = g.PlayToTurn(t)
+		if err != nil {
+			return err
+		}
+		fmt.Println(g.ToDisplayText())
+		if *step && t < last {
+			fmt.Print("(press Enter for the next turn)")
+			if _, err := stdin.ReadString('\n'); err != nil {
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/shell"
+)
+
+func runShell(cfg *config.Config, args []string) error {
+	fs, setup := newFlagSet("shell", cfg)
+	stopOnError := fs.Bool("stop-on-error", false, "stop at the first command that fails")
+	fs.Parse(args)
+	setup()
+
+	sh := shell.New(cfg, os.Stdin, os.Stdout)
+	sh.StopOnError = *stopOnError
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		// Only prompt when someone is typing the commands.
+		sh.Prompt = "cwgame> "
+	}
+	for _, cmd := range fs.Args() {
+		// Any arguments are run as commands first, e.g.
+		// cwgame shell "load game.gcg".
+		if err := sh.Execute(cmd); err != nil {
+			return err
+		}
+	}
+	return sh.Run()
+}
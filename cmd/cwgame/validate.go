@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+)
+
+// validate returns every problem found with a game in GCG form: all of
+// the diagnostics of the strict parser and, if lex is not nil, the words
+// played that are not in it. An error is returned if the game can't be
+// parsed at all.
+func validate(cfg *config.Config, gcg string, rules *game.GameRules, lex lexicon.Lexicon) ([]string, error) {
+	h, err := gcgio.ParseGCGFromReaderWithOptions(cfg, strings.NewReader(gcg),
+		gcgio.ParseOptions{Strict: true, Rules: rules})
+	problems := []string{}
+	var ds gcgio.Diagnostics
+	if errors.As(err, &ds) {
+		for _, d := range ds {
+			problems = append(problems, d.Error())
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if lex == nil {
+		return problems, nil
+	}
+	if h == nil {
+		// The strict parser doesn't return a game with problems in it.
+		h, err = gcgio.ParseGCGFromReaderWithOptions(cfg, strings.NewReader(gcg),
+			gcgio.ParseOptions{Rules: rules})
+		if err != nil {
+			return problems, nil
+		}
+	}
+	words, err := checkWords(h, rules, lex)
+	if err != nil {
+		return nil, err
+	}
+	return append(problems, words...), nil
+}
+
+// checkWords replays a history and returns a problem for every word
+// formed that is not in lex.
+func checkWords(h *pb.GameHistory, rules *game.GameRules, lex lexicon.Lexicon) ([]string, error) {
+	g, err := game.ReplayHistory(h, rules, 0)
+	if err != nil {
+		return nil, err
+	}
+	alph := g.Alphabet()
+	problems := []string{}
+	for t, evt := range h.Events {
+		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+			continue
+		}
+		report := func(format string, a ...interface{}) {
+			problems = append(problems, fmt.Sprintf("turn %d (%v): ", t+1, evt.Nickname)+
+				fmt.Sprintf(format, a...))
+		}
+		if err := g.PlayToTurn(t); err != nil {
+			report("cannot replay game up to here: %v", err)
+			break
+		}
+		m, err := g.CreateAndScorePlacementMove(evt.Position, evt.PlayedTiles, evt.Rack)
+		if err != nil {
+			report("illegal play %v %v: %v", evt.Position, evt.PlayedTiles, err)
+			continue
+		}
+		words, err := g.Board().FormedWords(m)
+		if err != nil {
+			report("cannot determine words formed: %v", err)
+			continue
+		}
+		challengedOff := t+1 < len(h.Events) &&
+			h.Events[t+1].Type == pb.GameEvent_PHONY_TILES_RETURNED
+		for _, w := range words {
+			if lex.HasWord(w) {
+				continue
+			}
+			if challengedOff {
+				report("%v is not a word in %v (it was challenged off)",
+					w.UserVisible(alph), lex.Name())
+			} else {
+				report("%v is not a word in %v", w.UserVisible(alph), lex.Name())
+			}
+		}
+	}
+	return problems, nil
+}
+
+func runValidate(cfg *config.Config, args []string) error {
+	fs, setup := newFlagSet("validate", cfg)
+	format := fs.String("format", "", "input format (gcg, json, pb or import); guessed from the extension if not given")
+	wordList := fs.String("lexicon", "", "word list file (one word per line) to check words against")
+	fs.Parse(args)
+	setup()
+	if fs.NArg() != 1 {
+		return errors.New("expected exactly one game file")
+	}
+
+	h, err := loadHistory(cfg, fs.Arg(0), *format)
+	if err != nil {
+		return err
+	}
+	rules, err := rulesFor(cfg, h)
+	if err != nil {
+		return err
+	}
+	var lex lexicon.Lexicon
+	if *wordList != "" {
+		lex, err = lexicon.LoadWordListFile(*wordList, rules.LetterDistribution().Alphabet())
+		if err != nil {
+			return err
+		}
+	}
+	// Games in other formats are checked as GCG, so the lines reported
+	// are those of the game converted to GCG.
+	var gcg string
+	if *format == "" {
+		*format, _ = formatFromFilename(fs.Arg(0))
+	}
+	if *format == formatGCG {
+		bts, err := ioutil.ReadFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		gcg = string(bts)
+	} else {
+		gcg, err = gcgio.GameHistoryToGCG(h, false)
+		if err != nil {
+			return err
+		}
+	}
+	problems, err := validate(cfg, gcg, rules, lex)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s)", len(problems))
+	}
+	fmt.Println("no problems found")
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	"github.com/domino14/cwgame/lexicon"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// loadGCG returns the contents of a GCG file with the replacements made,
+// and the rules of its game.
+func loadGCG(t *testing.T, filename string, replace ...string) (string, *game.GameRules) {
+	bts, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := strings.NewReplacer(replace...).Replace(string(bts))
+	h, err := gcgio.ParseGCGFromReader(&DefaultConfig, strings.NewReader(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := rulesFor(&DefaultConfig, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contents, rules
+}
+
+func TestValidateGoodGame(t *testing.T) {
+	is := is.New(t)
+	gcg, rules := loadGCG(t, "../../gcgio/testdata/doug_v_emely.gcg")
+	problems, err := validate(&DefaultConfig, gcg, rules, nil)
+	is.NoErr(err)
+	is.Equal(len(problems), 0)
+}
+
+func TestValidateBadScores(t *testing.T) {
+	is := is.New(t)
+	gcg, rules := loadGCG(t, "../../gcgio/testdata/doug_v_emely.gcg",
+		"8D WINDY +32 32", "8D WINDY +30 32",
+		"(OPEG) +14 345", "(OPEG) +12 343")
+	problems, err := validate(&DefaultConfig, gcg, rules, nil)
+	is.NoErr(err)
+	is.Equal(problems, []string{
+		"line 3, column 29: cumulative score is 32, but should be 30",
+		"line 3, column 26: 8D WINDY scores 32, not 30",
+		"line 30, column 18: rack OPEG is worth 14 points, not 12",
+	})
+}
+
+func TestValidateWords(t *testing.T) {
+	is := is.New(t)
+	gcg, rules := loadGCG(t, "../../gcgio/testdata/doug_v_emely.gcg")
+	alph := rules.LetterDistribution().Alphabet()
+	// A lexicon with only the first word; every other word is a phony.
+	lex, err := lexicon.NewWordList("TINY", alph, []string{"WINDY"})
+	is.NoErr(err)
+	problems, err := validate(&DefaultConfig, gcg, rules, lex)
+	is.NoErr(err)
+	is.True(len(problems) > 0)
+	is.Equal(problems[0], "turn 2 (emely): GALE is not a word in TINY")
+
+	// Phonies in a void game can be replayed with rules that know them.
+	h, err := gcgio.ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+	is.NoErr(err)
+	withLex := game.NewGameRules(&DefaultConfig, rules.LetterDistribution(), rules.Board(),
+		lex, rules.CrossSetGen())
+	words, err := checkWords(h, withLex, lex)
+	is.NoErr(err)
+	is.Equal(words, problems)
+}
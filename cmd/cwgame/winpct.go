@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/movegen"
+	"github.com/domino14/cwgame/winpct"
+)
+
+// runWinPct rebuilds a win percentage table from self-play games.
+func runWinPct(cfg *config.Config, args []string) error {
+	fs, setup := newFlagSet("winpct", cfg)
+	wordList := fs.String("lexicon", "", "word list file (one word per line) the games are played with")
+	dist := fs.String("letter-distribution", "English", "letter distribution of the games")
+	games := fs.Int("games", 1000, "number of games to play")
+	threads := fs.Int("threads", 0, "number of games to play at once; the number of CPUs if 0")
+	maxSpread := fs.Int("max-spread", winpct.DefaultMaxSpread, "largest spread the table tells apart")
+	limit := fs.Duration("time", 0, "stop after this long, keeping the games played so far")
+	out := fs.String("out", "winpct.json", "file to write the table to")
+	fs.Parse(args)
+	setup()
+	if *wordList == "" {
+		return errors.New("a word list is needed to play games with")
+	}
+
+	rules, err := game.NewBasicGameRules(cfg, board.CrosswordGameBoard, *dist)
+	if err != nil {
+		return err
+	}
+	ld := rules.LetterDistribution()
+	wl, err := lexicon.LoadWordListFile(*wordList, ld.Alphabet())
+	if err != nil {
+		return err
+	}
+	a := winpct.NewAutoplayer(rules, movegen.NewTrieGenerator(wl, ld), equity.NewLeaveCalculator(ld))
+	if *threads > 0 {
+		a.Threads = *threads
+	}
+	a.MaxSpread = *maxSpread
+
+	ctx := context.Background()
+	if *limit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *limit)
+		defer cancel()
+	}
+	start := time.Now()
+	t, err := a.Generate(ctx, *games)
+	if err != nil && err != context.DeadlineExceeded {
+		return err
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := t.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d positions to %v in %v\n", t.Positions(), *out,
+		time.Since(start).Round(time.Second))
+	return nil
+}
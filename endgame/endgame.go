@@ -0,0 +1,360 @@
+// Package endgame solves endgames: positions with an empty bag, where
+// both players know each other's racks and the best play can be found by
+// searching every line.
+package endgame
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+const (
+	// DefaultMaxPlies is how deep the solver searches by default.
+	DefaultMaxPlies = 10
+	// maxTableEntries is the size at which the transposition table is
+	// emptied, to keep memory in check.
+	maxTableEntries = 1 << 21
+	// checkEvery is how many nodes are searched between checks of the
+	// time limit.
+	checkEvery = 1024
+	infinity   = math.MaxInt32
+)
+
+// Solver searches endgames with negamax and alpha-beta pruning, deepening
+// one ply at a time until every line reaches the end of the game, the
+// maximum depth is reached, or time runs out.
+type Solver struct {
+	gen movegen.MoveGenerator
+
+	// MaxPlies is the deepest the search goes.
+	MaxPlies int
+	// TimeLimit stops the search, if it is not zero. The best sequence
+	// from the deepest search that was finished is returned.
+	TimeLimit time.Duration
+}
+
+// NewSolver creates a Solver that finds moves with the given generator.
+func NewSolver(gen movegen.MoveGenerator) *Solver {
+	return &Solver{gen: gen, MaxPlies: DefaultMaxPlies}
+}
+
+// Solution is the result of solving an endgame.
+type Solution struct {
+	// Moves is the best sequence found, starting with the move of the
+	// player on turn.
+	Moves []*move.Move
+	// Spread is the final spread of the player on turn, their points
+	// minus their opponent's, after the moves.
+	Spread int
+	// Depth is the depth of the deepest search that was finished.
+	Depth int
+	// Complete is true if every line was searched to the end of the game,
+	// so that the result is exact.
+	Complete bool
+	// Nodes is the number of positions searched.
+	Nodes int
+}
+
+type bound uint8
+
+const (
+	exact bound = iota
+	lower
+	upper
+)
+
+type entry struct {
+	depth    int
+	value    int
+	bound    bound
+	complete bool
+	best     string
+}
+
+// search is the state of one solve.
+type search struct {
+	gen     movegen.MoveGenerator
+	g       *game.Game
+	ctx     context.Context
+	table   map[uint64]entry
+	pv      [][]*move.Move
+	nodes   int
+	aborted bool
+	// err is why the search was aborted, if it wasn't the context.
+	err error
+}
+
+// Solve finds the best sequence of moves for the player on turn. g is not
+// changed.
+func (s *Solver) Solve(ctx context.Context, g *game.Game) (*Solution, error) {
+	if g.Bag().TilesRemaining() > 0 {
+		return nil, errors.New("the bag is not empty")
+	}
+	if g.Playing() == pb.PlayState_GAME_OVER {
+		return nil, errors.New("the game is over")
+	}
+	if s.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.TimeLimit)
+		defer cancel()
+	}
+	gc := g.Copy()
+	gc.SetBackupMode(game.SimulationMode)
+	gc.SetStateStackLength(s.MaxPlies + 1)
+
+	sr := &search{
+		gen:   s.gen,
+		g:     gc,
+		ctx:   ctx,
+		table: map[uint64]entry{},
+		pv:    make([][]*move.Move, s.MaxPlies+1),
+	}
+	spread := gc.SpreadFor(gc.PlayerOnTurn())
+	var sol *Solution
+	root := sr.moves("")
+	for depth := 1; depth <= s.MaxPlies; depth++ {
+		value, complete, ok := sr.root(root, depth)
+		if sr.err != nil {
+			return nil, sr.err
+		}
+		if !ok {
+			break
+		}
+		sol = &Solution{
+			Moves:    sr.principalVariation(),
+			Spread:   spread + value,
+			Depth:    depth,
+			Complete: complete,
+		}
+		log.Debug().Int("depth", depth).Int("value", value).Int("nodes", sr.nodes).
+			Bool("complete", complete).Msg("endgame-iteration")
+		if sr.aborted {
+			// Only part of this depth was searched.
+			sol.Depth = depth - 1
+			break
+		}
+		if complete {
+			break
+		}
+	}
+	if sol == nil {
+		// Not even one ply was searched; the best-scoring move will do.
+		sol = &Solution{Moves: root[:1], Spread: spread + root[0].Score()}
+	}
+	sol.Nodes = sr.nodes
+	return sol, nil
+}
+
+// root searches every root move to the given depth. The moves are sorted
+// by their values afterwards, so that the next iteration looks at the
+// best ones first. If time runs out part way through, the moves searched
+// so far are still used, as long as the best move of the last iteration
+// was one of them; ok is false if the result can't be used at all.
+func (sr *search) root(moves []*move.Move, depth int) (value int, complete, ok bool) {
+	for _, m := range moves {
+		m.SetVisited(false)
+	}
+	best := -infinity
+	complete = true
+	alpha, beta := -infinity, infinity
+	values := map[*move.Move]int{}
+	for _, m := range moves {
+		v, c := sr.play(m, depth, 0, alpha, beta)
+		if sr.aborted {
+			break
+		}
+		m.SetVisited(true)
+		values[m] = v
+		complete = complete && c
+		if v > best {
+			best = v
+			sr.pv[0] = append([]*move.Move{m}, sr.pv[1]...)
+		}
+		if v > alpha {
+			alpha = v
+		}
+	}
+	ok = !sr.aborted
+	if sr.aborted && sr.err == nil && moves[0].Visited() {
+		// The partial iteration is still better informed than the last.
+		ok, complete = true, false
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		vi, oki := values[moves[i]]
+		vj, okj := values[moves[j]]
+		if oki != okj {
+			return oki
+		}
+		return vi > vj
+	})
+	return best, complete, ok
+}
+
+// play plays m and searches the position after it, returning the value
+// of m for the player who plays it.
+func (sr *search) play(m *move.Move, depth, ply, alpha, beta int) (int, bool) {
+	me := sr.g.PlayerOnTurn()
+	before := sr.g.SpreadFor(me)
+	if err := sr.g.PlayMove(m, false, 0); err != nil {
+		sr.err = fmt.Errorf("playing %v: %v", m.ShortDescription(), err)
+		sr.aborted = true
+		return 0, false
+	}
+	delta := sr.g.SpreadFor(me) - before
+	child, complete := sr.negamax(depth-1, ply+1, delta-beta, delta-alpha)
+	sr.g.UnplayLastMove()
+	return delta - child, complete
+}
+
+// negamax returns how much the player on turn gains on their opponent
+// from here on, and whether every line was followed to the end of the
+// game.
+func (sr *search) negamax(depth, ply, alpha, beta int) (int, bool) {
+	sr.nodes++
+	if sr.nodes%checkEvery == 0 && sr.ctx.Err() != nil {
+		sr.aborted = true
+	}
+	if sr.aborted {
+		return 0, false
+	}
+	sr.pv[ply] = sr.pv[ply][:0]
+	if sr.g.Playing() == pb.PlayState_GAME_OVER {
+		return 0, true
+	}
+	if depth == 0 {
+		return 0, false
+	}
+
+	key := sr.key()
+	e, found := sr.table[key]
+	if found && (e.depth >= depth || (e.complete && e.bound == exact)) {
+		switch {
+		case e.bound == exact:
+			return e.value, e.complete
+		case e.bound == lower && e.value >= beta:
+			return e.value, e.complete
+		case e.bound == upper && e.value <= alpha:
+			return e.value, e.complete
+		}
+	}
+
+	alphaOrig := alpha
+	best, bestMove, complete := -infinity, "", true
+	for _, m := range sr.moves(e.best) {
+		v, c := sr.play(m, depth, ply, alpha, beta)
+		if sr.aborted {
+			return 0, false
+		}
+		complete = complete && c
+		if v > best {
+			best = v
+			bestMove = m.ShortDescription()
+			sr.pv[ply] = append(append(sr.pv[ply][:0], m), sr.pv[ply+1]...)
+		}
+		if v > alpha {
+			alpha = v
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	e = entry{depth: depth, value: best, complete: complete, best: bestMove}
+	switch {
+	case best <= alphaOrig:
+		e.bound = upper
+	case best >= beta:
+		e.bound = lower
+	default:
+		e.bound = exact
+	}
+	if len(sr.table) >= maxTableEntries {
+		sr.table = map[uint64]entry{}
+	}
+	sr.table[key] = e
+	return best, complete
+}
+
+// principalVariation is the best sequence from the last search. The
+// search itself only records it down to the first position that was
+// found in the transposition table, so the rest is filled in from the
+// best moves in the table.
+func (sr *search) principalVariation() []*move.Move {
+	pv := append([]*move.Move{}, sr.pv[0]...)
+	for _, m := range pv {
+		if err := sr.g.PlayMove(m, false, 0); err != nil {
+			log.Error().Err(err).Msg("endgame-pv")
+			return pv
+		}
+	}
+	played := len(pv)
+	for len(pv) < len(sr.pv)-1 && sr.g.Playing() != pb.PlayState_GAME_OVER {
+		e, ok := sr.table[sr.key()]
+		if !ok || e.bound != exact || e.best == "" {
+			break
+		}
+		var next *move.Move
+		for _, m := range sr.moves("") {
+			if m.ShortDescription() == e.best {
+				next = m
+				break
+			}
+		}
+		if next == nil || sr.g.PlayMove(next, false, 0) != nil {
+			break
+		}
+		pv = append(pv, next)
+		played++
+	}
+	for ; played > 0; played-- {
+		sr.g.UnplayLastMove()
+	}
+	return pv
+}
+
+// moves returns the moves for the player on turn, with the most promising
+// first: the best move found before, then plays that go out, then by
+// score and tiles played, with the pass last.
+func (sr *search) moves(first string) []*move.Move {
+	g := sr.g
+	if g.Playing() == pb.PlayState_WAITING_FOR_FINAL_PASS {
+		return []*move.Move{move.NewPassMove(g.RackFor(g.PlayerOnTurn()).TilesOn(), g.Alphabet())}
+	}
+	rack := g.RackFor(g.PlayerOnTurn())
+	moves := sr.gen.GenerateMoves(g.Board(), rack, 0)
+	for _, m := range moves {
+		v := float32(m.Score() + 3*m.TilesPlayed())
+		if m.Action() == move.MoveTypePlay && m.TilesPlayed() == int(rack.NumTiles()) {
+			v += 1000
+		}
+		if m.Action() == move.MoveTypePass {
+			v = -1000
+		}
+		if first != "" && m.ShortDescription() == first {
+			v = 1e6
+		}
+		m.SetValuation(v)
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].Valuation() > moves[j].Valuation()
+	})
+	return moves
+}
+
+// key identifies a position for the transposition table: the tiles on
+// the board, both racks, who is on turn and anything else that changes
+// how the game can go from here.
+func (sr *search) key() uint64 {
+	return sr.g.Hash()
+}
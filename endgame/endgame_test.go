@@ -0,0 +1,154 @@
+package endgame
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+var twos = []string{"AA", "AB", "AD", "AE", "AG", "AH", "AI", "AL", "AM", "AN",
+	"AR", "AS", "AT", "AW", "AX", "AY", "BA", "BE", "BI", "BO", "BY", "DE",
+	"DO", "ED", "EF", "EH", "EL", "EM", "EN", "ER", "ES", "EX", "FA", "FE",
+	"GO", "HA", "HE", "HI", "HM", "HO", "ID", "IF", "IN", "IS", "IT", "JO",
+	"KA", "KI", "LA", "LI", "LO", "MA", "ME", "MI", "MO", "MU", "MY", "NA",
+	"NE", "NO", "NU", "OD", "OE", "OF", "OH", "OI", "OM", "ON", "OP", "OR",
+	"OS", "OW", "OX", "OY", "PA", "PE", "PI", "QI", "RE", "SH", "SI", "SO",
+	"TA", "TI", "TO", "UH", "UM", "UN", "UP", "US", "UT", "WE", "WO", "XI",
+	"XU", "YA", "YE", "YO", "ZA"}
+
+// endgame sets up doug_v_emely after doug's HIM, with emely to play ?FS
+// against doug's EGOP and the bag empty. If racks are given, they replace
+// doug's and emely's racks, and the tiles left over are thrown away.
+func endgame(t *testing.T, racks ...string) (*game.Game, *movegen.TrieGenerator) {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, 26)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rules.LetterDistribution()
+	if len(racks) == 2 {
+		err = g.SetRacksForBoth([]*alphabet.Rack{
+			alphabet.RackFromString(racks[0], dist.Alphabet()),
+			alphabet.RackFromString(racks[1], dist.Alphabet())})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = g.Bag().RemoveTiles(g.Bag().Peek()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	words := append([]string{"FAS", "FEH", "GOPHER", "PEG", "POGO", "EGO", "GOES",
+		"FEHS", "OF", "IFS", "OFS", "PE", "PO", "GEO", "OP", "OPE"}, twos...)
+	wl, err := lexicon.NewWordList("test", dist.Alphabet(), words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, movegen.NewTrieGenerator(wl, dist)
+}
+
+// minimax searches every line to the end of the game without any
+// pruning, returning the gain for the player on turn.
+func minimax(g *game.Game, gen movegen.MoveGenerator) int {
+	if g.Playing() == pb.PlayState_GAME_OVER {
+		return 0
+	}
+	var moves []*move.Move
+	if g.Playing() == pb.PlayState_WAITING_FOR_FINAL_PASS {
+		moves = []*move.Move{move.NewPassMove(nil, g.Alphabet())}
+	} else {
+		moves = gen.GenerateMoves(g.Board(), g.RackFor(g.PlayerOnTurn()), 0)
+	}
+	best := -infinity
+	for _, m := range moves {
+		me := g.PlayerOnTurn()
+		before := g.SpreadFor(me)
+		if err := g.PlayMove(m, false, 0); err != nil {
+			panic(err)
+		}
+		v := g.SpreadFor(me) - before - minimax(g, gen)
+		g.UnplayLastMove()
+		if v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestSolve(t *testing.T) {
+	is := is.New(t)
+	g, gen := endgame(t, "OP", "FS")
+	is.Equal(g.Bag().TilesRemaining(), 0)
+	is.Equal(g.PlayerOnTurn(), 1)
+
+	s := NewSolver(gen)
+	s.MaxPlies = 20
+	sol, err := s.Solve(context.Background(), g)
+	is.NoErr(err)
+	is.True(sol.Complete)
+	is.True(len(sol.Moves) > 0)
+
+	bf := g.Copy()
+	bf.SetBackupMode(game.SimulationMode)
+	bf.SetStateStackLength(30)
+	want := g.SpreadFor(1) + minimax(bf, gen)
+	is.Equal(sol.Spread, want)
+
+	// Playing out the solution gives the spread it claims.
+	play := g.Copy()
+	for _, m := range sol.Moves {
+		is.NoErr(play.PlayMove(m, false, 0))
+	}
+	is.Equal(play.Playing(), pb.PlayState_GAME_OVER)
+	is.Equal(play.SpreadFor(1), sol.Spread)
+}
+
+func TestSolveLimits(t *testing.T) {
+	is := is.New(t)
+	g, gen := endgame(t)
+	is.Equal(g.Bag().TilesRemaining(), 0)
+	is.Equal(g.RackLettersFor(1), "FS?")
+	is.Equal(g.RackLettersFor(0), "EGOP")
+
+	s := NewSolver(gen)
+	s.MaxPlies = 1
+	sol, err := s.Solve(context.Background(), g)
+	is.NoErr(err)
+	is.Equal(sol.Depth, 1)
+	is.Equal(len(sol.Moves), 1)
+
+	s.MaxPlies = 20
+	s.TimeLimit = time.Nanosecond
+	sol, err = s.Solve(context.Background(), g)
+	is.NoErr(err)
+	is.True(len(sol.Moves) > 0)
+	// Running out of time stops the deepening.
+	is.True(!sol.Complete)
+	is.True(sol.Depth < s.MaxPlies)
+
+	// Not an endgame.
+	g.Bag().PutBack(g.RackFor(0).TilesOn())
+	_, err = s.Solve(context.Background(), g)
+	is.True(err != nil)
+}
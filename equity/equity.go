@@ -0,0 +1,42 @@
+// Package equity values moves by more than their score, so that the moves
+// from a move generator can be ranked.
+package equity
+
+import (
+	"sort"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/move"
+)
+
+// Calculator works out the equity of a move: its score plus the value of
+// everything else about it, in points.
+type Calculator interface {
+	// Equity returns the equity of m, played on b with the bag in the
+	// given state. oppRack is the opponent's rack, if it is known, and
+	// can be nil.
+	Equity(m *move.Move, b *board.GameBoard, bag *alphabet.Bag, oppRack *alphabet.Rack) float64
+}
+
+// ScoreOnly is a Calculator that values a move by its score alone.
+type ScoreOnly struct{}
+
+// Equity implements Calculator.
+func (ScoreOnly) Equity(m *move.Move, b *board.GameBoard, bag *alphabet.Bag,
+	oppRack *alphabet.Rack) float64 {
+	return float64(m.Score())
+}
+
+// Rank sets the equity of every move and sorts them from best to worst.
+// Moves with the same equity keep their order.
+func Rank(moves []*move.Move, calc Calculator, b *board.GameBoard, bag *alphabet.Bag,
+	oppRack *alphabet.Rack) {
+
+	for _, m := range moves {
+		m.SetEquity(calc.Equity(m, b, bag, oppRack))
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].Equity() > moves[j].Equity()
+	})
+}
@@ -0,0 +1,64 @@
+package equity
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/move"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func TestLeaveValue(t *testing.T) {
+	is := is.New(t)
+	dist, err := alphabet.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	alph := dist.Alphabet()
+	calc := NewLeaveCalculator(dist)
+	value := func(s string) float64 {
+		mw, err := alphabet.ToMachineWord(s, alph)
+		is.NoErr(err)
+		return calc.LeaveValue(mw)
+	}
+	is.Equal(value(""), 0.0)
+	is.Equal(value("?"), 25.0)
+	is.Equal(value("S"), 7.5)
+	// A second E is worth less than the first, and two vowels are unbalanced.
+	is.Equal(value("EE"), 4.0+4.0-duplicatePenalty-balancePenalty)
+	// Three vowels and no consonants.
+	is.Equal(value("AEI"), 1.0+4.0-1.0-2*balancePenalty)
+	is.True(value("ERS") > value("UVW"))
+}
+
+func TestRank(t *testing.T) {
+	is := is.New(t)
+	dist, err := alphabet.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	alph := dist.Alphabet()
+	calc := NewLeaveCalculator(dist)
+	bag := dist.MakeBag(rand.New(rand.NewSource(1)))
+
+	keepS := move.NewScoringMoveSimple(20, "8D", "CAT", "S", alph)
+	keepQ := move.NewScoringMoveSimple(24, "8D", "BAT", "Q", alph)
+	pass := move.NewPassMove(nil, alph)
+	moves := []*move.Move{keepQ, pass, keepS}
+	Rank(moves, calc, nil, bag, nil)
+	is.Equal(moves[0], keepS)
+	is.Equal(moves[0].Equity(), 27.5)
+	is.Equal(moves[1], keepQ)
+	is.Equal(moves[2], pass)
+
+	Rank(moves, ScoreOnly{}, nil, bag, nil)
+	is.Equal(moves[0], keepQ)
+
+	// With the bag empty, going out is worth twice the opponent's tiles.
+	is.NoErr(bag.RemoveTiles(bag.Peek()))
+	out := move.NewScoringMoveSimple(10, "8D", "CAT", "", alph)
+	opp := alphabet.RackFromString("QZ", alph)
+	is.Equal(calc.Equity(out, nil, bag, opp), 10.0+2*20)
+	is.Equal(calc.Equity(keepQ, nil, bag, opp), 24.0-2*10)
+}
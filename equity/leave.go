@@ -0,0 +1,108 @@
+package equity
+
+import (
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/move"
+)
+
+// englishTileValues are rough values of keeping a single tile of each
+// letter, in points.
+var englishTileValues = map[rune]float64{
+	'A': 1.0, 'B': -3.5, 'C': -0.5, 'D': 0.0, 'E': 4.0, 'F': -2.0, 'G': -2.5,
+	'H': 1.0, 'I': -1.0, 'J': -1.5, 'K': -1.5, 'L': -1.5, 'M': -0.5, 'N': 0.5,
+	'O': -2.5, 'P': -1.5, 'Q': -7.0, 'R': 1.5, 'S': 7.5, 'T': -0.5, 'U': -4.5,
+	'V': -6.5, 'W': -4.0, 'X': 3.5, 'Y': -1.5, 'Z': 3.0, alphabet.BlankToken: 25.0,
+}
+
+const (
+	// duplicatePenalty is taken off for every extra copy of a letter kept.
+	duplicatePenalty = 3.0
+	// balancePenalty is taken off for every vowel or consonant more than
+	// one that the leave is out of balance by.
+	balancePenalty = 1.5
+)
+
+// LeaveCalculator is a Calculator that adds an estimate of the value of
+// the tiles a move keeps to its score. Leaves are valued by the single
+// tiles they hold, with penalties for duplicates and for too many vowels
+// or consonants. Once the bag is empty the leave is worth nothing, but a
+// move that goes out gets twice the opponent's tiles, if their rack is
+// known, and one that doesn't loses twice its own.
+type LeaveCalculator struct {
+	dist   *alphabet.LetterDistribution
+	values map[alphabet.MachineLetter]float64
+}
+
+// NewLeaveCalculator creates a LeaveCalculator with the built-in values
+// for English tiles. Letters of other alphabets are valued at zero.
+func NewLeaveCalculator(dist *alphabet.LetterDistribution) *LeaveCalculator {
+	return NewLeaveCalculatorWithValues(dist, englishTileValues)
+}
+
+// NewLeaveCalculatorWithValues creates a LeaveCalculator with the given
+// values for keeping each tile; the blank is valued under '?'.
+func NewLeaveCalculatorWithValues(dist *alphabet.LetterDistribution,
+	values map[rune]float64) *LeaveCalculator {
+
+	c := &LeaveCalculator{dist: dist, values: map[alphabet.MachineLetter]float64{}}
+	alph := dist.Alphabet()
+	for r, v := range values {
+		if r == alphabet.BlankToken {
+			c.values[alphabet.BlankMachineLetter] = v
+		} else if ml, err := alph.Val(r); err == nil {
+			c.values[ml] = v
+		}
+	}
+	return c
+}
+
+// LeaveValue is the value of keeping the given tiles.
+func (c *LeaveCalculator) LeaveValue(leave alphabet.MachineWord) float64 {
+	alph := c.dist.Alphabet()
+	value := 0.0
+	seen := map[alphabet.MachineLetter]bool{}
+	vowels, consonants := 0, 0
+	for _, ml := range leave {
+		if ml.IsBlanked() {
+			ml = alphabet.BlankMachineLetter
+		}
+		value += c.values[ml]
+		if seen[ml] && ml != alphabet.BlankMachineLetter {
+			value -= duplicatePenalty
+		}
+		seen[ml] = true
+		switch {
+		case ml == alphabet.BlankMachineLetter:
+		case ml.IsVowel(alph):
+			vowels++
+		default:
+			consonants++
+		}
+	}
+	imbalance := vowels - consonants
+	if imbalance < 0 {
+		imbalance = -imbalance
+	}
+	if imbalance > 1 {
+		value -= balancePenalty * float64(imbalance-1)
+	}
+	return value
+}
+
+// Equity implements Calculator.
+func (c *LeaveCalculator) Equity(m *move.Move, b *board.GameBoard, bag *alphabet.Bag,
+	oppRack *alphabet.Rack) float64 {
+
+	score := float64(m.Score())
+	if bag.TilesRemaining() > 0 {
+		return score + c.LeaveValue(m.Leave())
+	}
+	if len(m.Leave()) == 0 {
+		if oppRack != nil {
+			score += 2 * float64(oppRack.ScoreOn(c.dist))
+		}
+		return score
+	}
+	return score - 2*float64(m.Leave().Score(c.dist))
+}
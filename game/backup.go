@@ -1,10 +1,13 @@
 package game
 
 import (
+	"sync/atomic"
+
 	"github.com/domino14/cwgame/alphabet"
 	"github.com/domino14/cwgame/board"
 	pb "github.com/domino14/cwgame/gen/proto/cwgame"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
 )
 
 type BackupMode int
@@ -150,8 +153,8 @@ func (g *Game) ResetToFirstState() {
 
 // Copy creates a deep copy of Game for the most part. The lexicon and
 // alphabet are not deep-copied because these are not expected to change.
-// The history is not copied because this only changes with the main Game,
-// and not these copies.
+// The history is shared with g until either of them writes to it, which
+// never happens in simulation mode; see ownHistory.
 // The bag is copied with a NEW random source, as random sources are not thread-safe.
 func (g *Game) Copy() *Game {
 
@@ -159,22 +162,66 @@ func (g *Game) Copy() *Game {
 	log.Debug().Msgf("Created new random seed for bag copy %v", randSeed)
 
 	copy := &Game{
-		config:         g.config,
-		onturn:         g.onturn,
-		turnnum:        g.turnnum,
-		board:          g.board.Copy(),
-		bag:            g.bag.Copy(randSource),
-		lexicon:        g.lexicon,
-		crossSetGen:    g.crossSetGen,
-		alph:           g.alph,
-		playing:        g.playing,
-		scorelessTurns: g.scorelessTurns,
-		players:        copyPlayers(g.players),
+		config:             g.config,
+		onturn:             g.onturn,
+		turnnum:            g.turnnum,
+		board:              g.board.Copy(),
+		letterDistribution: g.letterDistribution,
+		bag:                g.bag.Copy(randSource),
+		randSeed:           randSeed,
+		randSource:         randSource,
+		lexicon:            g.lexicon,
+		crossSetGen:        g.crossSetGen,
+		alph:               g.alph,
+		playing:            g.playing,
+		wentfirst:          g.wentfirst,
+		scorelessTurns:     g.scorelessTurns,
+		players:            copyPlayers(g.players),
+		nextFirst:          g.nextFirst,
 		// stackPtr only changes during a sim, etc. This Copy should
 		// only be called at the beginning of everything.
 		stackPtr: 0,
 	}
+	if g.history != nil {
+		if g.historyOwners != nil {
+			// Only the shared count is touched, so that copying g never
+			// changes g, and g can be copied from several goroutines.
+			atomic.AddInt32(g.historyOwners, 1)
+			copy.history = g.history
+			copy.historyOwners = g.historyOwners
+		} else {
+			copy.setHistory(proto.Clone(g.history).(*pb.GameHistory))
+		}
+	}
 	// Also set the copy's stack.
 	copy.SetStateStackLength(len(g.stateStack))
 	return copy
 }
+
+// setHistory makes h the history of g, owned by g alone.
+func (g *Game) setHistory(h *pb.GameHistory) {
+	owners := int32(1)
+	g.history = h
+	g.historyOwners = &owners
+}
+
+// ownHistory clones the history if it is shared with a copy of the game,
+// or the game this one was copied from, so that it can be written to.
+func (g *Game) ownHistory() {
+	if g.historyOwners == nil || atomic.LoadInt32(g.historyOwners) <= 1 {
+		return
+	}
+	h := proto.Clone(g.history).(*pb.GameHistory)
+	atomic.AddInt32(g.historyOwners, -1)
+	g.setHistory(h)
+}
+
+// recordPlayState writes the play state to the history. It is left alone
+// in simulation mode, where the history isn't written to.
+func (g *Game) recordPlayState() {
+	if g.backupMode == SimulationMode {
+		return
+	}
+	g.ownHistory()
+	g.history.PlayState = g.playing
+}
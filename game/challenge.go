@@ -14,6 +14,7 @@ import (
 // must already be started with StartGame above (call immediately afterwards).
 // It would default to the 0 state (VOID) otherwise.
 func (g *Game) SetChallengeRule(rule pb.ChallengeRule) {
+	g.ownHistory()
 	g.history.ChallengeRule = rule
 }
 
@@ -25,6 +26,7 @@ func (g *Game) SetChallengeRule(rule pb.ChallengeRule) {
 // out with a phony).
 // Return playLegal, error
 func (g *Game) ChallengeEvent(addlBonus int, millis int) (bool, error) {
+	g.ownHistory()
 	if len(g.history.Events) == 0 {
 		return false, errors.New("this game has no history")
 	}
@@ -55,7 +57,7 @@ func (g *Game) ChallengeEvent(addlBonus int, millis int) (bool, error) {
 			Rack:        lastEvent.Rack,
 			PlayedTiles: lastEvent.PlayedTiles,
 			// Note: these millis remaining would be the challenger's
-			MillisRemaining: int32(millis),
+			MillisRemaining: millisRemaining(millis),
 		}
 
 		// the play comes off the board. Add the offBoardEvent.
@@ -95,7 +97,7 @@ func (g *Game) ChallengeEvent(addlBonus int, millis int) (bool, error) {
 				Bonus:      bonus + int32(addlBonus),
 				Cumulative: cumeScoreBeforeChallenge + bonus + int32(addlBonus),
 				// Note: these millis remaining would be the challenger's
-				MillisRemaining: int32(millis),
+				MillisRemaining: millisRemaining(millis),
 			}
 		}
 
@@ -3,7 +3,6 @@ package game
 import (
 	"bytes"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/domino14/cwgame/alphabet"
@@ -61,19 +60,19 @@ func (g *Game) ToDisplayText() string {
 	addText(bts, vpadding+1, hpadding,
 		g.players[notfirst].stateString(g.playing == pb.PlayState_PLAYING && g.onturn == notfirst))
 
-	// Peek into the bag, and append the opponent's tiles:
-	inbag := g.bag.Peek()
-	opprack := g.players[otherPlayer(g.onturn)].rack.TilesOn()
-	bagAndUnseen := append(inbag, opprack...)
-	log.Debug().Str("inbag", alphabet.MachineWord(inbag).UserVisible(g.alph)).Msg("")
-	log.Debug().Str("opprack", alphabet.MachineWord(opprack).UserVisible(g.alph)).Msg("")
+	// Show what the player on turn can't see, without looking in the bag
+	// or at the opponent's rack.
+	var bagAndUnseen alphabet.MachineWord
+	unseen, err := g.UnseenFor(g.onturn)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot work out unseen tiles")
+	} else {
+		bagAndUnseen = unseen.Tiles
+	}
 
 	addText(bts, vpadding+3, hpadding, fmt.Sprintf("Bag + unseen: (%d)", len(bagAndUnseen)))
 
 	vpadding = 6
-	sort.Slice(bagAndUnseen, func(i, j int) bool {
-		return bagAndUnseen[i] < bagAndUnseen[j]
-	})
 
 	bagDisp := []string{}
 	cCtr := 0
@@ -104,8 +103,16 @@ func (g *Game) ToDisplayText() string {
 	}
 
 	if g.turnnum-1 >= 0 {
-		addText(bts, vpadding, hpadding,
-			summary(g.history.Events[g.turnnum-1]))
+		evt := g.history.Events[g.turnnum-1]
+		addText(bts, vpadding, hpadding, Summary(evt))
+		if evt.Annotation != nil {
+			// Only two lines fit before the end of the game is shown.
+			text := []rune(AnnotationSummary(evt.Annotation))
+			if len(text) > 84 {
+				text = append(text[:81], []rune("...")...)
+			}
+			addText(bts, vpadding+2, hpadding, string(text))
+		}
 	}
 
 	vpadding = 17
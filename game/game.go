@@ -19,6 +19,7 @@ import (
 	"github.com/domino14/cwgame/move"
 	"github.com/lithammer/shortuuid"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -74,6 +75,10 @@ type Game struct {
 	// history only gets written to when someone plays a move that is NOT
 	// backed up.
 	history *pb.GameHistory
+	// historyOwners counts the games sharing history: this one, its
+	// copies and the game it was copied from. If there is more than one,
+	// history has to be cloned before it is written to.
+	historyOwners *int32
 	// lastWordsFormed also does not need to be backed up, it only gets written
 	// to when the history is written to. See comment above.
 	lastWordsFormed []alphabet.MachineWord
@@ -112,6 +117,7 @@ func (g *Game) LastEvent() *pb.GameEvent {
 
 func (g *Game) addEventToHistory(evt *pb.GameEvent) {
 	log.Debug().Msgf("Adding event to history: %v", evt)
+	g.ownHistory()
 	g.history.Events = append(g.history.Events, evt)
 }
 
@@ -182,7 +188,7 @@ func NewFromHistory(history *pb.GameHistory, rules *GameRules, turnnum int) (*Ga
 	if err != nil {
 		return nil, err
 	}
-	game.history = history
+	game.setHistory(history)
 	if history.Uid == "" {
 		history.Uid = shortuuid.New()
 		history.IdAuth = IdentificationAuthority
@@ -209,6 +215,19 @@ func NewFromHistory(history *pb.GameHistory, rules *GameRules, turnnum int) (*Ga
 	return game, nil
 }
 
+// ReplayHistory is like NewFromHistory, for histories that may have
+// phonies in them that were never challenged off. A void challenge rule
+// would reject them while replaying, so a copy of the history is replayed
+// with a single challenge rule instead. The history passed in is not
+// changed.
+func ReplayHistory(history *pb.GameHistory, rules *GameRules, turnnum int) (*Game, error) {
+	history = proto.Clone(history).(*pb.GameHistory)
+	if history.ChallengeRule == pb.ChallengeRule_VOID {
+		history.ChallengeRule = pb.ChallengeRule_SINGLE
+	}
+	return NewFromHistory(history, rules, turnnum)
+}
+
 // SetNextFirst sets the player going first to the passed-in value. This
 // will take effect the next time StartGame is called.
 func (g *Game) SetNextFirst(first int) {
@@ -230,7 +249,7 @@ func (g *Game) StartGame() {
 		goesfirst = g.nextFirst
 		log.Debug().Msgf("forcing first to %v", g.nextFirst)
 	}
-	g.history = newHistory(g.players, goesfirst == 1)
+	g.setHistory(newHistory(g.players, goesfirst == 1))
 	// Deal out tiles
 	for i := 0; i < g.NumPlayers(); i++ {
 		tiles, err := g.bag.Draw(7)
@@ -358,11 +377,21 @@ func convertToVisible(words []alphabet.MachineWord,
 	return uvstrs
 }
 
+// millisRemaining returns the time remaining to record in an event, or nil
+// if no time was passed in.
+func millisRemaining(millis int) *int32 {
+	if millis == 0 {
+		return nil
+	}
+	return proto.Int32(int32(millis))
+}
+
 // PlayMove plays a move on the board. This function is meant to be used
 // by simulators as it implements a subset of possible moves, and by remote
 // gameplay engines as much as possible.
 // If the millis argument is passed in, it adds this value to the history
-// as the time remaining for the user (when they played the move).
+// as the time remaining for the user (when they played the move). A millis
+// of 0 means that no clock is kept, and nothing is added.
 func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 
 	// We need to handle challenges separately.
@@ -375,6 +404,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 		g.backupState()
 	}
 	if addToHistory {
+		g.ownHistory()
 		// Also, validate that the move follows the rules.
 		wordsFormed, err := g.ValidateMove(m)
 		if err != nil {
@@ -403,7 +433,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 
 		if addToHistory {
 			evt := g.EventFromMove(m)
-			evt.MillisRemaining = int32(millis)
+			evt.MillisRemaining = millisRemaining(millis)
 			evt.WordsFormed = convertToVisible(g.lastWordsFormed, g.alph)
 			g.history.LastKnownRacks[g.onturn] = g.RackLettersFor(g.onturn)
 			g.addEventToHistory(evt)
@@ -414,7 +444,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 				// Basically, if the challenge rule is not void,
 				// wait for the final pass (or challenge).
 				g.playing = pb.PlayState_WAITING_FOR_FINAL_PASS
-				g.history.PlayState = g.playing
+				g.recordPlayState()
 				log.Info().Msg("waiting for final pass... (commit pass)")
 			} else {
 				log.Debug().Msg("game is over")
@@ -435,7 +465,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 		// but that's not compatible with Quackle.
 		if g.playing == pb.PlayState_WAITING_FOR_FINAL_PASS {
 			g.playing = pb.PlayState_GAME_OVER
-			g.history.PlayState = g.playing
+			g.recordPlayState()
 			log.Debug().Msg("waiting -> gameover transition")
 			// Note that the player "on turn" changes here, as we created
 			// a fake virtual turn on the pass. We need to calculate
@@ -450,7 +480,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 			g.scorelessTurns++
 			if addToHistory {
 				evt := g.EventFromMove(m)
-				evt.MillisRemaining = int32(millis)
+				evt.MillisRemaining = millisRemaining(millis)
 				g.addEventToHistory(evt)
 			}
 		}
@@ -466,7 +496,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 		g.scorelessTurns++
 		if addToHistory {
 			evt := g.EventFromMove(m)
-			evt.MillisRemaining = int32(millis)
+			evt.MillisRemaining = millisRemaining(millis)
 			g.history.LastKnownRacks[g.onturn] = g.RackLettersFor(g.onturn)
 			g.addEventToHistory(evt)
 		}
@@ -489,6 +519,7 @@ func (g *Game) PlayMove(m *move.Move, addToHistory bool, millis int) error {
 
 // AddFinalScoresToHistory adds the final scores and winner to the history.
 func (g *Game) AddFinalScoresToHistory() {
+	g.ownHistory()
 	g.history.FinalScores = make([]int32, len(g.players))
 	for pidx, p := range g.players {
 		g.history.FinalScores[pidx] = int32(p.points)
@@ -509,7 +540,7 @@ func (g *Game) handleConsecutiveScorelessTurns(addToHistory bool) (bool, error)
 		ended = true
 		log.Debug().Msg("game ended with 6 scoreless turns")
 		g.playing = pb.PlayState_GAME_OVER
-		g.history.PlayState = g.playing
+		g.recordPlayState()
 
 		pts := g.calculateRackPts(g.onturn)
 		g.players[g.onturn].points -= pts
@@ -634,7 +665,7 @@ func (g *Game) PlayToTurn(turnnum int) error {
 		g.onturn = 1
 	}
 	g.playing = pb.PlayState_PLAYING
-	g.history.PlayState = g.playing
+	g.recordPlayState()
 	var t int
 	for t = 0; t < turnnum; t++ {
 		err := g.playTurn(t)
@@ -684,7 +715,7 @@ func (g *Game) PlayToTurn(turnnum int) error {
 		if p.rack.NumTiles() == 0 {
 			log.Debug().Msgf("Player %v has no tiles, game is over.", p)
 			g.playing = pb.PlayState_GAME_OVER
-			g.history.PlayState = g.playing
+			g.recordPlayState()
 
 			break
 		}
@@ -937,6 +968,18 @@ func (g *Game) Turn() int {
 	return g.turnnum
 }
 
+// SetTurn sets the turn number, which is the number of events in the
+// history that the game is at.
+func (g *Game) SetTurn(t int) {
+	g.turnnum = t
+}
+
+// ScorelessTurns is the number of scoreless turns in a row; the game ends
+// when it gets to six.
+func (g *Game) ScorelessTurns() int {
+	return g.scorelessTurns
+}
+
 func (g *Game) Uid() string {
 	return g.history.Uid
 }
@@ -986,7 +1029,7 @@ func (g *Game) History() *pb.GameHistory {
 }
 
 func (g *Game) SetHistory(h *pb.GameHistory) {
-	g.history = h
+	g.setHistory(h)
 }
 
 func (g *Game) FirstPlayer() *pb.PlayerInfo {
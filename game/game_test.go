@@ -2,6 +2,7 @@ package game
 
 import (
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/domino14/cwgame/alphabet"
@@ -67,6 +68,78 @@ func TestBackup(t *testing.T) {
 	is.Equal(game.players[0].rackLetters, "ACEOTV?")
 }
 
+func TestCopyPlaysToEnd(t *testing.T) {
+	is := is.New(t)
+	players := []*pb.PlayerInfo{
+		{Nickname: "JD", RealName: "Jesse"},
+		{Nickname: "cesar", RealName: "César"},
+	}
+	rules, _ := NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	game, _ := NewGame(rules, players)
+	game.StartGame()
+
+	cp := game.Copy()
+	for i := 0; i < 6; i++ {
+		is.NoErr(cp.PlayMove(move.NewPassMove(cp.RackFor(cp.PlayerOnTurn()).TilesOn(),
+			cp.Alphabet()), false, 0))
+	}
+	is.Equal(cp.Playing(), pb.PlayState_GAME_OVER)
+	is.Equal(cp.History().PlayState, pb.PlayState_GAME_OVER)
+	// The original game is untouched.
+	is.Equal(game.Playing(), pb.PlayState_PLAYING)
+	is.Equal(game.History().PlayState, pb.PlayState_PLAYING)
+	_, err := cp.UnseenFor(0)
+	is.NoErr(err)
+
+	// In simulation mode the history is shared instead of cloned.
+	sim := game.Copy()
+	sim.SetBackupMode(SimulationMode)
+	sim.SetStateStackLength(7)
+	for i := 0; i < 6; i++ {
+		is.NoErr(sim.PlayMove(move.NewPassMove(sim.RackFor(sim.PlayerOnTurn()).TilesOn(),
+			sim.Alphabet()), false, 0))
+	}
+	is.Equal(sim.Playing(), pb.PlayState_GAME_OVER)
+	is.True(sim.History() == game.History())
+	is.Equal(game.History().PlayState, pb.PlayState_PLAYING)
+
+	// Writing to the original's history doesn't change the copy's.
+	is.NoErr(game.PlayMove(move.NewPassMove(game.RackFor(game.PlayerOnTurn()).TilesOn(),
+		game.Alphabet()), true, 0))
+	is.Equal(len(game.History().Events), 1)
+	is.Equal(len(sim.History().Events), 0)
+}
+
+func TestCopyConcurrently(t *testing.T) {
+	is := is.New(t)
+	players := []*pb.PlayerInfo{
+		{Nickname: "JD", RealName: "Jesse"},
+		{Nickname: "cesar", RealName: "César"},
+	}
+	rules, _ := NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	game, _ := NewGame(rules, players)
+	game.StartGame()
+
+	// Copying only reads the game, so it can be done from several
+	// goroutines at once, each of which then writes its own history.
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cp := game.Copy()
+			errs[i] = cp.PlayMove(move.NewPassMove(cp.RackFor(cp.PlayerOnTurn()).TilesOn(),
+				cp.Alphabet()), true, 0)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		is.NoErr(err)
+	}
+	is.Equal(len(game.History().Events), 0)
+}
+
 func TestValidate(t *testing.T) {
 	is := is.New(t)
 	players := []*pb.PlayerInfo{
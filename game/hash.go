@@ -0,0 +1,57 @@
+package game
+
+import (
+	"math/rand"
+
+	"github.com/domino14/cwgame/alphabet"
+)
+
+// The keys that Hash combines with the board's hash. They are seeded, like
+// the board's, so that hashes are the same from run to run.
+var (
+	rackKeys      [2][alphabet.MaxAlphabetSize + 1][RackTileLimit]uint64
+	onTurnKey     uint64
+	playStateKeys [3]uint64
+	// scorelessKeys has a key for every number of scoreless turns that
+	// can be reached before the game ends.
+	scorelessKeys [16]uint64
+)
+
+func init() {
+	r := rand.New(rand.NewSource(0x4a5))
+	for p := range rackKeys {
+		for ml := range rackKeys[p] {
+			for n := range rackKeys[p][ml] {
+				rackKeys[p][ml][n] = r.Uint64()
+			}
+		}
+	}
+	onTurnKey = r.Uint64()
+	for i := range playStateKeys {
+		playStateKeys[i] = r.Uint64()
+	}
+	for i := range scorelessKeys {
+		scorelessKeys[i] = r.Uint64()
+	}
+}
+
+// Hash returns a 64-bit Zobrist hash of the position: the tiles on the
+// board, both racks, who is on turn, and the play state and number of
+// scoreless turns, which change how the game can go from here. Scores and
+// the bag are not part of it.
+func (g *Game) Hash() uint64 {
+	h := g.board.Hash()
+	for p := range g.players {
+		for ml, n := range g.players[p].rack.LetArr {
+			for i := 0; i < n; i++ {
+				h ^= rackKeys[p][ml][i]
+			}
+		}
+	}
+	if g.onturn == 1 {
+		h ^= onTurnKey
+	}
+	h ^= playStateKeys[g.playing]
+	h ^= scorelessKeys[g.scorelessTurns%len(scorelessKeys)]
+	return h
+}
@@ -8,7 +8,8 @@ import (
 )
 
 // HistoryToVariant takes in a game history and returns the board configuration
-// and letter distribution name.
+// and letter distribution name. A board layout or letter distribution named
+// in the history takes precedence over the variant and lexicon.
 func HistoryToVariant(h *pb.GameHistory) (boardLayout []string, letterDistributionName string) {
 
 	switch h.Variant {
@@ -17,8 +18,13 @@ func HistoryToVariant(h *pb.GameHistory) (boardLayout []string, letterDistributi
 	default:
 		boardLayout = board.CrosswordGameBoard
 	}
+	if layout, ok := board.Layouts[h.BoardLayout]; ok {
+		boardLayout = layout
+	}
 	letterDistributionName = "english"
 	switch {
+	case h.LetterDistribution != "":
+		letterDistributionName = strings.ToLower(h.LetterDistribution)
 	case strings.HasPrefix(h.Lexicon, "OSPS"):
 		letterDistributionName = "polish"
 	case strings.HasPrefix(h.Lexicon, "FISE"):
@@ -1,6 +1,7 @@
 package game_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/domino14/cwgame/board"
@@ -126,3 +127,23 @@ func TestNewFromHistoryIncomplete7(t *testing.T) {
 	is.True(g.Playing() == pb.PlayState_PLAYING)
 	is.Equal(g.RackLettersFor(1), "AEEIILZ")
 }
+
+func TestDisplayAnnotation(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard,
+		"English")
+	is.NoErr(err)
+	gameHistory, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	a := &pb.Annotation{
+		Tags:       []string{"missed bingo"},
+		Candidates: []*pb.CandidatePlay{{Description: "F2 VEX", Score: 40, Equity: 41.25}},
+		Variations: []*pb.Variation{{}, {}},
+	}
+	gameHistory.Events[3].Annotation = a
+	is.Equal(game.AnnotationSummary(a), "[missed bingo] best: F2 VEX 40 (41.2) 2 variations")
+
+	g, err := game.NewFromHistory(gameHistory, rules, 4)
+	is.NoErr(err)
+	is.True(strings.Contains(g.ToDisplayText(), "[missed bingo] best: F2 VEX 40 (41.2)"))
+}
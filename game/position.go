@@ -0,0 +1,118 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/domino14/cwgame/alphabet"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/rs/zerolog/log"
+)
+
+// A Position is a game position to set up, for puzzles and analysis.
+type Position struct {
+	// Rows has a string for every row of the board from the top, in the
+	// form used by board.GameBoard.SetRow: a space is an empty square
+	// and a lowercase letter is a blank. Rows that are left out, and the
+	// ends of short rows, are empty.
+	Rows []string
+	// Board is the board in the compact form of
+	// board.GameBoard.CompactString, used instead of Rows if it is not
+	// empty.
+	Board string
+	// Racks are the racks of the players. A rack that is empty is drawn
+	// at random from the bag, if there are tiles in it.
+	Racks  [2]string
+	Scores [2]int
+	OnTurn int
+	// ScorelessTurns is the number of scoreless turns in a row that led
+	// up to the position.
+	ScorelessTurns int
+}
+
+// NewFromPosition creates a game at the given position. The bag has
+// every tile of the letter distribution that is not on the board or a
+// rack. The history of the game starts at the position, so it can't be
+// replayed back to an earlier turn.
+func NewFromPosition(rules *GameRules, players []*pb.PlayerInfo, pos *Position) (*Game, error) {
+	if len(players) != 2 {
+		return nil, errors.New("a position needs two players")
+	}
+	if pos.OnTurn != 0 && pos.OnTurn != 1 {
+		return nil, fmt.Errorf("player on turn must be 0 or 1, not %d", pos.OnTurn)
+	}
+	if pos.ScorelessTurns < 0 {
+		return nil, errors.New("the number of scoreless turns can't be negative")
+	}
+	g, err := NewGame(rules, players)
+	if err != nil {
+		return nil, err
+	}
+	g.randSeed, g.randSource = seededRandSource()
+	log.Debug().Msgf("Position - Random seed for this game was %v", g.randSeed)
+
+	var onBoard alphabet.MachineWord
+	if pos.Board != "" {
+		onBoard, err = g.board.SetFromCompact(pos.Board, g.alph)
+	} else {
+		onBoard, err = g.board.SetRows(pos.Rows, g.alph)
+	}
+	if err != nil {
+		return nil, err
+	}
+	g.board.UpdateAllAnchors()
+	g.crossSetGen.GenerateAll(g.board)
+
+	racks := [2]alphabet.MachineWord{}
+	for i, rack := range pos.Racks {
+		if racks[i], err = alphabet.ToMachineWord(rack, g.alph); err != nil {
+			return nil, err
+		}
+		if len(racks[i]) > RackTileLimit {
+			return nil, fmt.Errorf("rack %v has more than %d tiles", rack, RackTileLimit)
+		}
+		for _, ml := range racks[i] {
+			if ml != alphabet.BlankMachineLetter && (ml.IsBlanked() ||
+				ml == alphabet.PlayedThroughMarker || ml == alphabet.SeparationMachineLetter) {
+				return nil, fmt.Errorf("rack %v has a tile that can't be on a rack", rack)
+			}
+		}
+	}
+	// Check that there are enough of every tile.
+	both := append(append(alphabet.MachineWord{}, racks[0]...), racks[1]...)
+	if _, err = UnseenTiles(g.letterDistribution, g.board, both); err != nil {
+		return nil, err
+	}
+
+	g.bag = g.letterDistribution.MakeBag(g.randSource)
+	if err = g.bag.RemoveTiles(append(onBoard, both...)); err != nil {
+		return nil, err
+	}
+	for i, p := range g.players {
+		p.rack = alphabet.NewRack(g.alph)
+		if len(racks[i]) == 0 && g.bag.TilesRemaining() > 0 {
+			racks[i] = g.bag.DrawAtMost(RackTileLimit)
+		}
+		p.setRackTiles(racks[i], g.alph)
+		p.points = pos.Scores[i]
+	}
+
+	g.setHistory(newHistory(g.players, false))
+	g.history.Lexicon = g.Lexicon().Name()
+	g.history.LastKnownRacks = []string{g.RackLettersFor(0), g.RackLettersFor(1)}
+	g.onturn = pos.OnTurn
+	g.scorelessTurns = pos.ScorelessTurns
+	g.playing = pb.PlayState_PLAYING
+	if g.bag.TilesRemaining() == 0 &&
+		(g.RackFor(0).NumTiles() == 0 || g.RackFor(1).NumTiles() == 0) {
+		g.playing = pb.PlayState_GAME_OVER
+	}
+	g.history.PlayState = g.playing
+	return g, nil
+}
+
+// CompactBoard returns the board of the game on one line, as written by
+// board.GameBoard.CompactString.
+func (g *Game) CompactBoard() string {
+	return g.board.CompactString(g.alph)
+}
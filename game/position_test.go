@@ -0,0 +1,160 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+var players = []*pb.PlayerInfo{
+	{Nickname: "doug", UserId: "doug"},
+	{Nickname: "emely", UserId: "emely"},
+}
+
+// rows returns the rows of the board of g, as in game.Position.Rows.
+func rows(g *game.Game) []string {
+	b := g.Board()
+	lines := []string{}
+	for r := 0; r < b.Dim(); r++ {
+		row := []rune{}
+		for c := 0; c < b.Dim(); c++ {
+			if b.HasLetter(r, c) {
+				row = append(row, b.GetLetter(r, c).UserVisible(g.Alphabet()))
+			} else {
+				row = append(row, ' ')
+			}
+		}
+		lines = append(lines, string(row))
+	}
+	return lines
+}
+
+func TestNewFromPosition(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	// emely to play ?FS against doug's EGOP.
+	orig, err := game.NewFromHistory(h, rules, 26)
+	is.NoErr(err)
+
+	g, err := game.NewFromPosition(rules, players, &game.Position{
+		Rows:   rows(orig),
+		Racks:  [2]string{"EGOP", "?FS"},
+		Scores: [2]int{451, 310},
+		OnTurn: 1,
+	})
+	is.NoErr(err)
+	is.Equal(rows(g), rows(orig))
+	is.True(g.Board().Equals(orig.Board()))
+	is.Equal(g.Bag().TilesRemaining(), 0)
+	is.Equal(g.PlayerOnTurn(), 1)
+	is.Equal(g.SpreadFor(0), 141)
+	is.Equal(g.Playing(), pb.PlayState_PLAYING)
+
+	m, err := g.CreateAndScorePlacementMove("14L", ".aFS", "?FS")
+	is.NoErr(err)
+	is.Equal(m.Score(), 21)
+	is.NoErr(g.PlayMove(m, true, 0))
+	is.Equal(g.History().Events[0].Cumulative, int32(331))
+	// emely went out, and gets doug's EGOP.
+	is.Equal(g.Playing(), pb.PlayState_GAME_OVER)
+	is.Equal(g.PointsFor(1), 345)
+}
+
+func TestNewFromPositionRows(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	setup := make([]string, 8)
+	setup[7] = "   WInDY"
+	g, err := game.NewFromPosition(rules, players, &game.Position{
+		Rows:   setup,
+		Racks:  [2]string{"", "AEI?"},
+		Scores: [2]int{30, 0},
+		OnTurn: 1,
+	})
+	is.NoErr(err)
+	is.Equal(g.Board().TilesPlayed(), 5)
+	is.Equal(rows(g)[7], "   WInDY       ")
+	is.Equal(g.CompactBoard(), "15/15/15/15/15/15/15/3WInDY7/15/15/15/15/15/15/15")
+	// doug's rack is drawn from the bag.
+	is.Equal(int(g.RackFor(0).NumTiles()), 7)
+	is.Equal(g.RackFor(1).String(), "AEI?")
+	is.Equal(g.Bag().TilesRemaining(), 100-5-7-4)
+	unseen, err := g.UnseenFor(1)
+	is.NoErr(err)
+	// Both blanks are seen, as the n on the board is one of them.
+	is.Equal(unseen.Blanks, 0)
+	is.Equal(unseen.Counts['N'], 6)
+}
+
+func TestNewFromPositionErrors(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	for _, pos := range []*game.Position{
+		{Rows: []string{"ZZ"}, Racks: [2]string{"Z"}},
+		{Rows: []string{"A?"}},
+		{Rows: []string{"A.B"}},
+		{Rows: []string{"ABCDEFGHIJKLMNOP"}},
+		{Racks: [2]string{"ABCDEFGH"}},
+		{Racks: [2]string{"Ab"}},
+		{OnTurn: 2},
+		{Rows: []string{"WINDY", "WINDY", "WINDY", "WINDY", "WINDY"}},
+		{Board: "15/15"},
+		{Board: "15/15/15/15/15/15/15/3WINDY6/15/15/15/15/15/15/15"},
+	} {
+		_, err := game.NewFromPosition(rules, players, pos)
+		is.True(err != nil)
+	}
+}
+
+func TestHash(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	orig, err := game.NewFromHistory(h, rules, 26)
+	is.NoErr(err)
+	g, err := game.NewFromPosition(rules, players, &game.Position{
+		Board:  orig.CompactBoard(),
+		Racks:  [2]string{"EGOP", "?FS"},
+		Scores: [2]int{451, 310},
+		OnTurn: 1,
+	})
+	is.NoErr(err)
+	is.Equal(g.Hash(), orig.Hash())
+	is.True(g.Hash() != g.Board().Hash())
+
+	// Swapping the racks or who is on turn changes the hash.
+	swapped, err := game.NewFromPosition(rules, players, &game.Position{
+		Board:  orig.CompactBoard(),
+		Racks:  [2]string{"?FS", "EGOP"},
+		Scores: [2]int{451, 310},
+		OnTurn: 1,
+	})
+	is.NoErr(err)
+	is.True(swapped.Hash() != g.Hash())
+	swapped, err = game.NewFromPosition(rules, players, &game.Position{
+		Board:  orig.CompactBoard(),
+		Racks:  [2]string{"EGOP", "?FS"},
+		Scores: [2]int{451, 310},
+	})
+	is.NoErr(err)
+	is.True(swapped.Hash() != g.Hash())
+
+	m, err := g.CreateAndScorePlacementMove("14L", ".aFS", "?FS")
+	is.NoErr(err)
+	is.NoErr(g.PlayMove(m, true, 0))
+	is.True(g.Hash() != orig.Hash())
+	is.NoErr(orig.PlayMove(m, true, 0))
+	is.Equal(g.Hash(), orig.Hash())
+}
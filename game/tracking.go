@@ -0,0 +1,138 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+// Unseen are the tiles that a player has not seen: every tile of the
+// letter distribution that is not on the board or on their own rack.
+// They are in the bag or on the opponent's rack, and a player can work
+// them out without looking at either.
+type Unseen struct {
+	// Tiles are in alphabet order, with blanks last.
+	Tiles alphabet.MachineWord
+	// Counts has the number of each letter unseen; blanks are under '?'.
+	Counts     map[rune]int
+	Vowels     int
+	Consonants int
+	Blanks     int
+}
+
+// UnseenTiles works out the tiles unseen by a player with the given rack.
+func UnseenTiles(dist *alphabet.LetterDistribution, b *board.GameBoard,
+	rack alphabet.MachineWord) (*Unseen, error) {
+
+	alph := dist.Alphabet()
+	counts := map[alphabet.MachineLetter]int{}
+	for r, n := range dist.Distribution {
+		ml, err := alph.Val(r)
+		if err != nil {
+			return nil, err
+		}
+		counts[ml] += int(n)
+	}
+	seen := append(alphabet.MachineWord{}, rack...)
+	for r := 0; r < b.Dim(); r++ {
+		for c := 0; c < b.Dim(); c++ {
+			if ml := b.GetLetter(r, c); ml != alphabet.EmptySquareMarker {
+				seen = append(seen, ml)
+			}
+		}
+	}
+	for _, ml := range seen {
+		if ml.IsBlanked() {
+			ml = alphabet.BlankMachineLetter
+		}
+		counts[ml]--
+		if counts[ml] < 0 {
+			return nil, fmt.Errorf("there are more %c tiles on the board and rack than in the bag",
+				ml.UserVisible(alph))
+		}
+	}
+
+	vowels := map[rune]bool{}
+	for _, v := range dist.Vowels {
+		vowels[v] = true
+	}
+	u := &Unseen{Counts: map[rune]int{}}
+	for ml, n := range counts {
+		if n == 0 {
+			continue
+		}
+		r := ml.UserVisible(alph)
+		u.Counts[r] = n
+		switch {
+		case ml == alphabet.BlankMachineLetter:
+			u.Blanks += n
+		case vowels[r]:
+			u.Vowels += n
+		default:
+			u.Consonants += n
+		}
+		for i := 0; i < n; i++ {
+			u.Tiles = append(u.Tiles, ml)
+		}
+	}
+	sort.Slice(u.Tiles, func(i, j int) bool { return u.Tiles[i] < u.Tiles[j] })
+	return u, nil
+}
+
+// UnseenFor returns the tiles unseen by a player at the current position.
+func (g *Game) UnseenFor(playerIdx int) (*Unseen, error) {
+	return UnseenTiles(g.letterDistribution, g.board, g.players[playerIdx].rack.TilesOn())
+}
+
+// UnseenAtTurn returns the tiles unseen by a player after the given number
+// of events of a history. The player's rack is the one they have on their
+// next turn, or at the end of the game; if it isn't known, its tiles count
+// as unseen too.
+func UnseenAtTurn(h *pb.GameHistory, rules *GameRules, turn, playerIdx int) (*Unseen, error) {
+	if turn < 0 || turn > len(h.Events) {
+		return nil, fmt.Errorf("turn %d is out of range", turn)
+	}
+	g, err := ReplayHistory(h, rules, turn)
+	if err != nil {
+		return nil, err
+	}
+	rack, err := alphabet.ToMachineWord(rackAtTurn(h, turn, playerIdx), g.alph)
+	if err != nil {
+		return nil, err
+	}
+	return UnseenTiles(g.letterDistribution, g.board, rack)
+}
+
+// rackAtTurn finds the rack a player has after the given number of
+// events. A player's rack doesn't change until their next move, so it is
+// the rack recorded with that move.
+func rackAtTurn(h *pb.GameHistory, turn, playerIdx int) string {
+	nick := h.Players[playerIdx].Nickname
+	for _, evt := range h.Events[turn:] {
+		if evt.Type == pb.GameEvent_END_RACK_PTS {
+			// The rack here is the one the opponent was left with.
+			if evt.Nickname != nick {
+				return evt.Rack
+			}
+			continue
+		}
+		if evt.Nickname == nick && evt.Rack != "" {
+			return evt.Rack
+		}
+	}
+	// At the end of the game, the tiles left are counted against the
+	// player, and that is all there is to see.
+	if n := len(h.Events); n > 0 {
+		last := h.Events[n-1]
+		if last.Type == pb.GameEvent_END_RACK_PTS && last.Nickname != nick {
+			return last.Rack
+		}
+	}
+	if len(h.LastKnownRacks) > playerIdx {
+		return h.LastKnownRacks[playerIdx]
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+)
+
+func TestUnseenAtTurn(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	alph := rules.LetterDistribution().Alphabet()
+
+	u, err := game.UnseenAtTurn(h, rules, 0, 0)
+	is.NoErr(err)
+	// Everything but doug's DINNVWY.
+	is.Equal(len(u.Tiles), 93)
+	is.Equal(u.Counts['N'], 4)
+	is.Equal(u.Counts['W'], 1)
+	is.Equal(u.Counts['?'], 2)
+	is.Equal(u.Blanks, 2)
+	is.Equal(u.Vowels+u.Consonants+u.Blanks, 93)
+	is.Equal(u.Vowels, 41)
+	is.Equal(u.Tiles[0:9].UserVisible(alph), "AAAAAAAAA")
+
+	// After WINDY, doug already holds his next rack AEJNOSV.
+	u, err = game.UnseenAtTurn(h, rules, 1, 0)
+	is.NoErr(err)
+	is.Equal(len(u.Tiles), 88)
+	_, ok := u.Counts['J']
+	is.True(!ok)
+
+	// At the end, doug was left with OPEG and has seen everything.
+	u, err = game.UnseenAtTurn(h, rules, len(h.Events), 0)
+	is.NoErr(err)
+	is.Equal(len(u.Tiles), 0)
+
+	_, err = game.UnseenAtTurn(h, rules, len(h.Events)+1, 0)
+	is.True(err != nil)
+}
+
+func TestUnseenFor(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+
+	g, err := game.NewFromHistory(h, rules, 1)
+	is.NoErr(err)
+	live, err := g.UnseenFor(1)
+	is.NoErr(err)
+	replayed, err := game.UnseenAtTurn(h, rules, 1, 1)
+	is.NoErr(err)
+	is.Equal(live.Tiles, replayed.Tiles)
+	// The unseen tiles are the bag and doug's rack, but they are worked
+	// out without looking at either.
+	is.Equal(len(live.Tiles), g.Bag().TilesRemaining()+int(g.RackFor(0).NumTiles()))
+}
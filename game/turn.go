@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/domino14/cwgame/alphabet"
 	"github.com/domino14/cwgame/board"
@@ -258,7 +259,8 @@ func Leave(rack alphabet.MachineWord, play alphabet.MachineWord) (alphabet.Machi
 	return leave, nil
 }
 
-func summary(evt *pb.GameEvent) string {
+// Summary returns a short, human-readable description of an event.
+func Summary(evt *pb.GameEvent) string {
 	summary := ""
 	switch evt.Type {
 	case pb.GameEvent_TILE_PLACEMENT_MOVE:
@@ -300,3 +302,22 @@ func summary(evt *pb.GameEvent) string {
 
 	return summary
 }
+
+// AnnotationSummary returns a one-line description of an annotation: its
+// tags, the first candidate play and the number of variations.
+func AnnotationSummary(a *pb.Annotation) string {
+	parts := []string{}
+	if len(a.Tags) > 0 {
+		parts = append(parts, "["+strings.Join(a.Tags, ", ")+"]")
+	}
+	if len(a.Candidates) > 0 {
+		c := a.Candidates[0]
+		parts = append(parts, fmt.Sprintf("best: %s %d (%.1f)", c.Description, c.Score, c.Equity))
+	}
+	if n := len(a.Variations); n == 1 {
+		parts = append(parts, "1 variation")
+	} else if n > 1 {
+		parts = append(parts, fmt.Sprintf("%d variations", n))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,56 @@
+package gcgio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The time a player has left after an event is written in a #time pragma
+// following the event, as minutes and seconds with optional milliseconds,
+// for example:
+//
+//	>doug: DINNVWY 8D WINDY +32 32
+//	#time 12:03.250
+//
+// A player who went over time has a negative time remaining, and one
+// whose clock ran out exactly has #time 0:00.000. Events without a #time
+// have no clock data, and are read with no millis remaining set.
+
+// formatClock formats milliseconds as m:ss.mmm.
+func formatClock(millis int32) string {
+	sign := ""
+	ms := int64(millis)
+	if ms < 0 {
+		sign = "-"
+		ms = -ms
+	}
+	return fmt.Sprintf("%s%d:%02d.%03d", sign, ms/60000, ms/1000%60, ms%1000)
+}
+
+// parseClock parses a time in the format written by formatClock. The
+// milliseconds may be left out or given with fewer digits.
+func parseClock(s string) (int32, error) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	colon := strings.Index(s, ":")
+	if colon < 0 {
+		return 0, fmt.Errorf("bad time %v", s)
+	}
+	mins, err := strconv.ParseInt(s[:colon], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("bad time %v: %v", s, err)
+	}
+	secs, err := strconv.ParseFloat(s[colon+1:], 64)
+	if err != nil || secs >= 60 {
+		return 0, fmt.Errorf("bad time %v", s)
+	}
+	ms := mins*60000 + int64(secs*1000+0.5)
+	if ms > 1<<31-1 {
+		return 0, fmt.Errorf("time %v is too long", s)
+	}
+	if neg {
+		ms = -ms
+	}
+	return int32(ms), nil
+}
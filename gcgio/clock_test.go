@@ -0,0 +1,75 @@
+package gcgio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestClockFormat(t *testing.T) {
+	is := is.New(t)
+	for _, tc := range []struct {
+		millis int32
+		clock  string
+	}{
+		{723250, "12:03.250"},
+		{1000, "0:01.000"},
+		{-5500, "-0:05.500"},
+		{3600000, "60:00.000"},
+	} {
+		is.Equal(formatClock(tc.millis), tc.clock)
+		millis, err := parseClock(tc.clock)
+		is.NoErr(err)
+		is.Equal(millis, tc.millis)
+	}
+
+	millis, err := parseClock("12:03")
+	is.NoErr(err)
+	is.Equal(millis, int32(723000))
+	millis, err = parseClock("1:02.5")
+	is.NoErr(err)
+	is.Equal(millis, int32(62500))
+	_, err = parseClock("1:75")
+	is.True(err != nil)
+}
+
+func TestParseTime(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+#time 12:03.250
+#note a good start
+>emely: ADEEGIL 7C GALE +16 16
+>doug: AEJNOSV E3 JAVE..N +34 66
+#time -0:02
+>emely: DEIILTZ -DIZ +0 16
+#time 0:00
+`))
+	is.NoErr(err)
+	is.Equal(h.Events[0].GetMillisRemaining(), int32(723250))
+	is.Equal(h.Events[0].Note, "a good start")
+	is.True(h.Events[1].MillisRemaining == nil)
+	is.Equal(h.Events[2].GetMillisRemaining(), int32(-2000))
+	// A clock that ran out exactly is not the same as no clock.
+	is.True(h.Events[3].MillisRemaining != nil)
+	is.Equal(*h.Events[3].MillisRemaining, int32(0))
+
+	gcg, err := GameHistoryToGCG(h, false)
+	is.NoErr(err)
+	is.True(strings.Contains(gcg, "+32 32\n#time 12:03.250\n#note a good start\n"))
+	is.True(strings.Contains(gcg, "+16 16\n>doug"))
+	is.True(strings.HasSuffix(gcg, "+0 16\n#time 0:00.000\n"))
+
+	h2 := roundTrip(t, h)
+	h.OriginalGcg, h2.OriginalGcg = "", ""
+	is.True(proto.Equal(h, h2))
+
+	_, err = ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+#time 12:03.250
+`))
+	is.True(err != nil)
+}
@@ -13,12 +13,15 @@ import (
 	"strings"
 
 	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
 	"github.com/domino14/cwgame/config"
-
+	"github.com/domino14/cwgame/cross_set"
 	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/lexicon"
 
 	"golang.org/x/text/encoding/charmap"
 	"golang.org/x/text/transform"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	pb "github.com/domino14/cwgame/gen/proto/cwgame"
 	"github.com/rs/zerolog/log"
@@ -29,6 +32,7 @@ var (
 	errPragmaPrecedeEvent = errors.New("non-note pragmata should appear before event lines")
 	errEncodingWrongPlace = errors.New("encoding line must be first line in file if present")
 	errPlayerNotSupported = errors.New("player number not supported")
+	errRulesAfterEvents   = errors.New("the rules of the game can't change once it has started")
 )
 
 // A Token is an event in a GCG file.
@@ -53,6 +57,12 @@ const (
 	EndRackPointsToken
 	TimePenaltyToken
 	LastRackPenaltyToken
+	ChallengeRuleToken
+	VariantToken
+	BoardLayoutToken
+	TileDistributionToken
+	TimeToken
+	UnsuccessfulChallengeToken
 )
 
 type gcgdatum struct {
@@ -70,9 +80,14 @@ const (
 	Rack1Regex              = `#rack1 (?P<rack>\S+)`
 	Rack2Regex              = `#rack2 (?P<rack>\S+)`
 	MoveRegex               = `>(?P<nick>\S+):\s+(?P<rack>\S+)\s+(?P<pos>\w+)\s+(?P<play>[\w\\.]+)\s+\+(?P<score>\d+)\s+(?P<cumul>\d+)`
-	NoteRegex               = `#note (?P<note>.+)`
+	NoteRegex               = `#note(?:\s(?P<note>.*))?$`
 	LexiconRegex            = `#lexicon (?P<lexicon>.+)`
 	CharacterEncodingRegex  = `#character-encoding (?P<encoding>[[:graph:]]+)`
+	ChallengeRuleRegex      = `#challenge-rule\s+(?P<rule>\S+)`
+	VariantRegex            = `#variant\s+(?P<variant>\S+)`
+	BoardLayoutRegex        = `#board-layout\s+(?P<layout>\S+)`
+	TileDistributionRegex   = `#tile-distribution\s+(?P<distribution>\S+)`
+	TimeRegex               = `#time\s+(?P<time>-?\d+:\d{2}(?:\.\d{1,3})?)\s*$`
 	PhonyTilesReturnedRegex = `>(?P<nick>\S+):\s+(?P<rack>\S+)\s+--\s+-(?P<lost_score>\d+)\s+(?P<cumul>\d+)`
 	PassRegex               = `>(?P<nick>\S+):\s+(?P<rack>\S+)\s+-\s+\+0\s+(?P<cumul>\d+)`
 	ChallengeBonusRegex     = `>(?P<nick>\S+):\s+(?P<rack>\S*)\s+\(challenge\)\s+\+(?P<bonus>\d+)\s+(?P<cumul>\d+)`
@@ -82,13 +97,71 @@ const (
 	PtsLostForLastRackRegex = `>(?P<nick>\S+):\s+(?P<rack>\S+)\s+\((?P<rack>\S+)\)\s+\-(?P<penalty>\d+)\s+(?P<cumul>-?\d+)`
 )
 
+// UnsuccessfulChallengeRegex marks the pass before it as a turn lost to an
+// unsuccessful challenge, which GCG has no other way of saying.
+const UnsuccessfulChallengeRegex = `#unsuccessful-challenge\s*$`
+
+// legacyUnsuccessfulChallengeNote is how a lost turn used to be marked: a
+// note with this text, before any other note of the pass. It is still
+// read, but no longer written.
+const legacyUnsuccessfulChallengeNote = "#unsuccessful-challenge"
+
 var compiledEncodingRegexp *regexp.Regexp
 
+// annotationNote starts a note holding the annotation of an event as JSON.
+// Other programs show it as an ordinary note.
+const annotationNote = "@annotation "
+
+// ParseOptions control how a GCG is parsed.
+type ParseOptions struct {
+	// Strict makes the parser check every event against the game instead
+	// of trusting the file: scores are recomputed, cumulative totals,
+	// racks and end-of-game points are checked, and all of the problems
+	// found are returned together as Diagnostics.
+	Strict bool
+	// Rules are used to replay the game instead of rules made from its
+	// variant, board layout and tile distribution.
+	Rules *game.GameRules
+	// Lexicon is used to judge the words played if Rules is not set. It is
+	// only consulted if the challenge rule of the game is void.
+	Lexicon lexicon.Lexicon
+	// NoDefaults leaves the lexicon and variant of the history empty if
+	// the GCG doesn't give them, instead of filling in the defaults, so
+	// that writing the history out again adds nothing that wasn't there.
+	NoDefaults bool
+}
+
 type parser struct {
 	lastToken Token
 
 	history *pb.GameHistory
 	game    *game.Game
+
+	opts ParseOptions
+	// The line being parsed, and the indices of the submatches of the
+	// regex that it matched, for reporting where problems are.
+	lineNum    int
+	line       string
+	submatches []int
+
+	diagnostics Diagnostics
+	// challengeRule is the challenge rule given in the file, if any. The
+	// game is replayed with a single challenge rule otherwise.
+	challengeRule pb.ChallengeRule
+	// noteLines is the number of #note lines for the last event.
+	noteLines int
+	// noteBlanks is the number of blank lines since the last line of a
+	// note. They are only kept in the note if more of it follows.
+	noteBlanks int
+	// fatal is set when an event could not be played, after which the
+	// rest of the game can't be checked.
+	fatal bool
+	// cumes and kept are the cumulative score in the file and the tiles
+	// kept after the last turn for each player, and totals are the sums
+	// of the scores written, for strict checking.
+	cumes  map[string]int32
+	totals map[string]int32
+	kept   map[string]alphabet.MachineWord
 }
 
 // init initializes the regexp list.
@@ -101,6 +174,9 @@ func init() {
 	compiledEncodingRegexp = regexp.MustCompile(CharacterEncodingRegex)
 
 	GCGRegexes = []gcgdatum{
+		// Notes are matched first, and only at the start of a line, so
+		// that the text of a note can't be taken for anything else.
+		{NoteToken, regexp.MustCompile(`^\s*` + NoteRegex)},
 		{PlayerToken, regexp.MustCompile(PlayerRegex)},
 		{TitleToken, regexp.MustCompile(TitleRegex)},
 		{DescriptionToken, regexp.MustCompile(DescriptionRegex)},
@@ -109,8 +185,13 @@ func init() {
 		{Rack2Token, regexp.MustCompile(Rack2Regex)},
 		{EncodingToken, compiledEncodingRegexp},
 		{MoveToken, regexp.MustCompile(MoveRegex)},
-		{NoteToken, regexp.MustCompile(NoteRegex)},
 		{LexiconToken, regexp.MustCompile(LexiconRegex)},
+		{ChallengeRuleToken, regexp.MustCompile(ChallengeRuleRegex)},
+		{VariantToken, regexp.MustCompile(VariantRegex)},
+		{BoardLayoutToken, regexp.MustCompile(BoardLayoutRegex)},
+		{TileDistributionToken, regexp.MustCompile(TileDistributionRegex)},
+		{TimeToken, regexp.MustCompile(TimeRegex)},
+		{UnsuccessfulChallengeToken, regexp.MustCompile(UnsuccessfulChallengeRegex)},
 		{PhonyTilesReturnedToken, regexp.MustCompile(PhonyTilesReturnedRegex)},
 		{PassToken, regexp.MustCompile(PassRegex)},
 		{ChallengeBonusToken, regexp.MustCompile(ChallengeBonusRegex)},
@@ -138,17 +219,19 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 			return errors.New("wrong number of players defined")
 		}
 		if p.game == nil {
-
-			if p.history.Variant == "" {
+			if p.history.Variant == "" && !p.opts.NoDefaults {
 				p.history.Variant = "CrosswordGame"
 			}
 			if p.history.Lexicon == "" {
-				p.history.Lexicon = cfg.DefaultLexicon
+				if p.opts.Lexicon != nil {
+					p.history.Lexicon = p.opts.Lexicon.Name()
+				} else if !p.opts.NoDefaults {
+					p.history.Lexicon = cfg.DefaultLexicon
+				}
 			}
-			boardLayout, letterDistributionName := game.HistoryToVariant(p.history)
 
 			// We have both players. Initialize a new game.
-			rules, err := game.NewBasicGameRules(cfg, boardLayout, letterDistributionName)
+			rules, err := p.rules(cfg)
 			if err != nil {
 				return err
 			}
@@ -199,17 +282,21 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 			return errPragmaPrecedeEvent
 		}
 		p.history.Description = match[1]
+		return nil
 	case IDToken:
 		if len(p.history.Events) > 0 {
 			return errPragmaPrecedeEvent
 		}
 		p.history.IdAuth = match[1]
 		p.history.Uid = match[2]
+		return nil
 	case Rack1Token:
-		// assume if there is a rack2 token, that rack1 will come before it.
 		if p.history.LastKnownRacks == nil {
 			p.history.LastKnownRacks = []string{match[1], ""}
+		} else {
+			p.history.LastKnownRacks[0] = match[1]
 		}
+		return nil
 	case Rack2Token:
 		if p.history.LastKnownRacks == nil {
 			p.history.LastKnownRacks = []string{"", match[1]}
@@ -217,21 +304,170 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 			// There is already a rack1 at the [0] position.
 			p.history.LastKnownRacks[1] = match[1]
 		}
+		return nil
 	case EncodingToken:
 		return errEncodingWrongPlace
+	case NoteToken:
+		if len(p.history.Events) == 0 {
+			// There is nothing to attach the note to, so keep it as is.
+			p.addUnknownPragma(p.line)
+			return nil
+		}
+		evt := p.history.Events[len(p.history.Events)-1]
+		if p.noteLines == 0 && evt.Type == pb.GameEvent_PASS &&
+			strings.TrimSpace(match[1]) == legacyUnsuccessfulChallengeNote {
+			evt.Type = pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS
+			return nil
+		}
+		if strings.HasPrefix(match[1], annotationNote) {
+			a := &pb.Annotation{}
+			err := protojson.Unmarshal([]byte(strings.TrimPrefix(match[1], annotationNote)), a)
+			if err == nil {
+				evt.Annotation = a
+				return nil
+			}
+			// Otherwise it is kept as part of the note.
+			log.Debug().Err(err).Msg("note-is-not-an-annotation")
+		}
+		if p.noteLines > 0 {
+			// Each line of a multi-line note can have its own #note.
+			evt.Note += "\n"
+		}
+		evt.Note += match[1]
+		p.noteLines++
+		return nil
+	case LexiconToken:
+		if len(p.history.Events) > 0 {
+			return errPragmaPrecedeEvent
+		}
+		p.history.Lexicon = match[1]
+		return nil
+	case UnsuccessfulChallengeToken:
+		if len(p.history.Events) == 0 ||
+			p.history.Events[len(p.history.Events)-1].Type != pb.GameEvent_PASS {
+			return errors.New("an unsuccessful challenge must follow a pass")
+		}
+		p.history.Events[len(p.history.Events)-1].Type = pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS
+		return nil
+	case TimeToken:
+		if len(p.history.Events) == 0 {
+			return errors.New("the time remaining must follow an event")
+		}
+		millis, err := parseClock(match[1])
+		if err != nil {
+			return err
+		}
+		p.history.Events[len(p.history.Events)-1].MillisRemaining = &millis
+		return nil
+	case ChallengeRuleToken:
+		if len(p.history.Events) > 0 {
+			return errRulesAfterEvents
+		}
+		rule, ok := pb.ChallengeRule_value[strings.ToUpper(match[1])]
+		if !ok {
+			return fmt.Errorf("unknown challenge rule %v", match[1])
+		}
+		p.challengeRule = pb.ChallengeRule(rule)
+		p.history.ChallengeRule = p.challengeRule
+		return nil
+	case VariantToken:
+		if len(p.history.Events) > 0 {
+			return errRulesAfterEvents
+		}
+		p.history.Variant = match[1]
+		return nil
+	case BoardLayoutToken:
+		if len(p.history.Events) > 0 {
+			return errRulesAfterEvents
+		}
+		if _, ok := board.Layouts[match[1]]; !ok {
+			return fmt.Errorf("unknown board layout %v", match[1])
+		}
+		p.history.BoardLayout = match[1]
+		return nil
+	case TileDistributionToken:
+		if len(p.history.Events) > 0 {
+			return errRulesAfterEvents
+		}
+		p.history.LetterDistribution = match[1]
+		return nil
+	}
+
+	// Everything else is an event.
+	if p.game == nil {
+		return errors.New("the first event must be a play, pass or exchange")
+	}
+	evt, err := p.eventFromMatch(token, match)
+	if err != nil {
+		return err
+	}
+	if p.opts.Strict {
+		err = p.checkEvent(evt)
+		if err != nil {
+			p.fatal = true
+			return err
+		}
+	}
+	p.history.Events = append(p.history.Events, evt)
+	p.noteLines = 0
+	log.Debug().Int("type", int(evt.Type)).Msg("playing latest event")
+
+	switch evt.Type {
+	case pb.GameEvent_TIME_PENALTY, pb.GameEvent_END_RACK_PTS:
+		// End the game.
+		p.game.SetPlaying(pb.PlayState_GAME_OVER)
+	}
+	err = p.game.PlayLatestEvent()
+	if evt.Type == pb.GameEvent_END_RACK_PENALTY {
+		// End the game.
+		p.game.SetPlaying(pb.PlayState_GAME_OVER)
+	}
+	if err != nil {
+		p.fatal = true
+	}
+	return err
+}
+
+// rules returns the rules to replay the game with.
+func (p *parser) rules(cfg *config.Config) (*game.GameRules, error) {
+	if p.opts.Rules != nil {
+		return p.opts.Rules, nil
+	}
+	h := &pb.GameHistory{Lexicon: p.history.Lexicon, Variant: p.history.Variant,
+		BoardLayout: p.history.BoardLayout, LetterDistribution: p.history.LetterDistribution}
+	if h.Lexicon == "" {
+		h.Lexicon = cfg.DefaultLexicon
+	}
+	boardLayout, letterDistributionName := game.HistoryToVariant(h)
+	if p.opts.Lexicon == nil {
+		return game.NewBasicGameRules(cfg, boardLayout, letterDistributionName)
+	}
+	dist, err := alphabet.LoadLetterDistribution(cfg, letterDistributionName)
+	if err != nil {
+		return nil, err
+	}
+	return game.NewGameRules(cfg, dist, board.MakeBoard(boardLayout), p.opts.Lexicon,
+		cross_set.CrossScoreOnlyGenerator{Dist: dist}), nil
+}
+
+// eventFromMatch creates the event for an event line.
+func (p *parser) eventFromMatch(token Token, match []string) (*pb.GameEvent, error) {
+	var err error
+	evt := &pb.GameEvent{}
+	evt.Nickname = match[1]
+	evt.Rack = match[2]
+
+	switch token {
 	case MoveToken:
-		evt := &pb.GameEvent{}
-		evt.Nickname = match[1]
-		evt.Rack = match[2]
 		evt.Position = match[3]
 		evt.PlayedTiles = match[4]
 		evt.Score, err = matchToInt32(match[5])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		evt.Cumulative, err = matchToInt32(match[6])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		game.CalculateCoordsFromStringPosition(evt)
 		evt.Type = pb.GameEvent_TILE_PLACEMENT_MOVE
@@ -244,172 +480,131 @@ func (p *parser) addEventOrPragma(cfg *config.Config, token Token, match []strin
 		}
 
 		evt.IsBingo = tp == 7
-		p.history.Events = append(p.history.Events, evt)
-		// Try playing the move
-		log.Debug().Msg("PLAYING LATEST EVENT for MoveToken")
 
-		return p.game.PlayLatestEvent()
-
-	case NoteToken:
-		lastEvtIdx := len(p.history.Events) - 1
-		p.history.Events[lastEvtIdx].Note += match[1]
-		return nil
-	case LexiconToken:
-		if len(p.history.Events) > 0 {
-			return errPragmaPrecedeEvent
-		}
-		p.history.Lexicon = match[1]
-		return nil
 	case PhonyTilesReturnedToken:
-		evt := &pb.GameEvent{}
-		evt.Nickname = match[1]
-		evt.Rack = match[2]
-
-		score, err := matchToInt32(match[3])
+		evt.LostScore, err = matchToInt32(match[3])
 		if err != nil {
-			return err
+			return nil, err
 		}
-		evt.LostScore = score
 		evt.Cumulative, err = matchToInt32(match[4])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		// The PlayedTiles attribute should be set to the LAST event's played tiles
 		if len(p.history.Events) == 0 {
-			return errors.New("malformed gcg; phony tiles returned without play")
+			return nil, errors.New("malformed gcg; phony tiles returned without play")
 		}
 		evt.PlayedTiles = p.history.Events[len(p.history.Events)-1].PlayedTiles
 		evt.Type = pb.GameEvent_PHONY_TILES_RETURNED
-		p.history.Events = append(p.history.Events, evt)
-		log.Debug().Msg("PLAYING LATEST EVENT for PhonytilesReturned")
-		return p.game.PlayLatestEvent()
 
 	case TimePenaltyToken:
-		evt := &pb.GameEvent{}
-		evt.Nickname = match[1]
-		evt.Rack = match[2]
-
-		score, err := matchToInt32(match[3])
+		evt.LostScore, err = matchToInt32(match[3])
 		if err != nil {
-			return err
+			return nil, err
 		}
-		evt.LostScore = score
 		evt.Cumulative, err = matchToInt32(match[4])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		// Treat this as a stand-alone turn; it should not be attached to
 		// the previous event because it can occur after the wrong player
 		// (i.e. player2 goes out, and then time penalty is applied to player1)
-
 		evt.Type = pb.GameEvent_TIME_PENALTY
-		p.history.Events = append(p.history.Events, evt)
-		p.game.SetPlaying(pb.PlayState_GAME_OVER)
-
-		err = p.game.PlayLatestEvent()
-		if err != nil {
-			return err
-		}
 
 	case LastRackPenaltyToken:
-		evt := &pb.GameEvent{}
-		evt.Nickname = match[1]
-		evt.Rack = match[2]
 		if evt.Rack != match[3] {
-			return fmt.Errorf("last rack penalty event malformed")
+			return nil, fmt.Errorf("last rack penalty event malformed")
 		}
-		score, err := matchToInt32(match[4])
+		evt.LostScore, err = matchToInt32(match[4])
 		if err != nil {
-			return err
+			return nil, err
 		}
-		evt.LostScore = score
 		evt.Cumulative, err = matchToInt32(match[5])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		evt.Type = pb.GameEvent_END_RACK_PENALTY
-		p.history.Events = append(p.history.Events, evt)
-		err = p.game.PlayLatestEvent()
-		// End the game.
-		p.game.SetPlaying(pb.PlayState_GAME_OVER)
-		if err != nil {
-			return err
-		}
 
 	case PassToken:
-		evt := &pb.GameEvent{}
-		evt.Nickname = match[1]
-		evt.Rack = match[2]
 		evt.Cumulative, err = matchToInt32(match[3])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		evt.Type = pb.GameEvent_PASS
-		p.history.Events = append(p.history.Events, evt)
-		return p.game.PlayLatestEvent()
 
 	case ChallengeBonusToken, EndRackPointsToken:
-		evt := &pb.GameEvent{}
-		evt.Nickname = match[1]
-		evt.Rack = match[2]
 		if token == ChallengeBonusToken {
 			evt.Bonus, err = matchToInt32(match[3])
 		} else {
 			evt.EndRackPoints, err = matchToInt32(match[3])
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 		evt.Cumulative, err = matchToInt32(match[4])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if token == ChallengeBonusToken {
 			evt.Type = pb.GameEvent_CHALLENGE_BONUS
-		} else if token == EndRackPointsToken {
+		} else {
 			evt.Type = pb.GameEvent_END_RACK_PTS
-			// End the game.
-			p.game.SetPlaying(pb.PlayState_GAME_OVER)
 		}
-		p.history.Events = append(p.history.Events, evt)
-		return p.game.PlayLatestEvent()
 
 	case ExchangeToken:
-		evt := &pb.GameEvent{}
-		evt.Nickname = match[1]
-		evt.Rack = match[2]
 		evt.Exchanged = match[3]
 		evt.Cumulative, err = matchToInt32(match[4])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		evt.Type = pb.GameEvent_EXCHANGE
-		p.history.Events = append(p.history.Events, evt)
-		return p.game.PlayLatestEvent()
 
+	default:
+		return nil, fmt.Errorf("unhandled token %v", token)
 	}
-	return nil
+	return evt, nil
 }
 
 func (p *parser) parseLine(cfg *config.Config, line string) error {
 
 	foundMatch := false
+	p.line = line
 
 	for _, datum := range GCGRegexes {
-		match := datum.regex.FindStringSubmatch(line)
-		if match != nil {
+		p.submatches = datum.regex.FindStringSubmatchIndex(line)
+		if p.submatches != nil {
 			foundMatch = true
+			match := make([]string, len(p.submatches)/2)
+			for i := range match {
+				if p.submatches[2*i] >= 0 {
+					match[i] = line[p.submatches[2*i]:p.submatches[2*i+1]]
+				}
+			}
 			err := p.addEventOrPragma(cfg, datum.token, match)
 			if err != nil {
 				return err
 			}
 			p.lastToken = datum.token
+			p.noteBlanks = 0
 			break
 		}
 	}
 	if !foundMatch {
-		// maybe it's a multi-line note.
+		// maybe it's a multi-line note, as Quackle writes them.
 		if p.lastToken == NoteToken {
+			if strings.TrimSpace(line) == "" {
+				p.noteBlanks++
+				return nil
+			}
+			line = strings.Repeat("\n", p.noteBlanks) + line
+			p.noteBlanks = 0
+			if len(p.history.Events) == 0 {
+				// The note was kept as a header pragma; see
+				// addEventOrPragma.
+				last := len(p.history.UnknownPragmas) - 1
+				p.history.UnknownPragmas[last] += "\n" + line
+				return nil
+			}
 			lastEventIdx := len(p.history.Events) - 1
 			p.history.Events[lastEventIdx].Note += ("\n" + line)
 			return nil
@@ -418,11 +613,48 @@ func (p *parser) parseLine(cfg *config.Config, line string) error {
 		if strings.TrimSpace(line) == "" {
 			return nil
 		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			p.addUnknownPragma(line)
+			return nil
+		}
 		return fmt.Errorf("no match found for line '%v'", line)
 	}
 	return nil
 }
 
+// addUnknownPragma keeps a pragma that the parser doesn't understand,
+// so that it can be written out again.
+func (p *parser) addUnknownPragma(line string) {
+	if len(p.history.Events) == 0 {
+		p.history.UnknownPragmas = append(p.history.UnknownPragmas, line)
+		return
+	}
+	evt := p.history.Events[len(p.history.Events)-1]
+	evt.UnknownPragmas = append(evt.UnknownPragmas, line)
+}
+
+// parseNextLine parses a line, and returns an error if parsing should
+// stop. In strict mode, problems with a line are collected and parsing
+// continues if possible.
+func (p *parser) parseNextLine(cfg *config.Config, line string) error {
+	p.lineNum++
+	p.submatches = nil
+	err := p.parseLine(cfg, line)
+	if err == nil || !p.opts.Strict {
+		return err
+	}
+	d, ok := err.(*Diagnostic)
+	if !ok {
+		d = p.diagnostic(0, err)
+	}
+	p.diagnostics = append(p.diagnostics, d)
+	if p.fatal {
+		// The game can't be followed any further.
+		return p.diagnostics
+	}
+	return nil
+}
+
 func encodingOrFirstLine(reader io.Reader) (string, string, error) {
 	// Read either the encoding of the file, or the first line,
 	// whichever is available.
@@ -462,7 +694,18 @@ func encodingOrFirstLine(reader io.Reader) (string, string, error) {
 	}
 }
 
+// ParseGCGFromReader parses a GCG from the reader into a GameHistory,
+// trusting the scores in it.
 func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory, error) {
+	return ParseGCGFromReaderWithOptions(cfg, reader, ParseOptions{})
+}
+
+// ParseGCGFromReaderWithOptions parses a GCG from the reader into a
+// GameHistory. In strict mode, the problems found are returned together
+// as Diagnostics, with the position of each one.
+func ParseGCGFromReaderWithOptions(cfg *config.Config, reader io.Reader,
+	opts ParseOptions) (*pb.GameHistory, error) {
+
 	var err error
 	parser := &parser{
 		history: &pb.GameHistory{
@@ -472,6 +715,10 @@ func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory,
 			// check the validity of every play.
 			ChallengeRule: pb.ChallengeRule_SINGLE,
 			Version:       1},
+		opts:   opts,
+		cumes:  map[string]int32{},
+		totals: map[string]int32{},
+		kept:   map[string]alphabet.MachineWord{},
 	}
 	originalGCG := ""
 
@@ -491,40 +738,54 @@ func ParseGCGFromReader(cfg *config.Config, reader io.Reader) (*pb.GameHistory,
 		scanner = bufio.NewScanner(reader)
 	}
 	if firstLine != "" {
-		err = parser.parseLine(cfg, firstLine)
+		err = parser.parseNextLine(cfg, firstLine)
 		if err != nil {
 			return nil, err
 		}
 		originalGCG += firstLine + "\n"
+	} else {
+		// The first line was the encoding, or blank.
+		parser.lineNum++
 	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
-		err = parser.parseLine(cfg, line)
+		err = parser.parseNextLine(cfg, line)
 		if err != nil {
 			return nil, err
 		}
 		originalGCG += line + "\n"
 	}
+	if len(parser.diagnostics) > 0 {
+		return nil, parser.diagnostics
+	}
 	parser.history.OriginalGcg = strings.TrimSpace(originalGCG)
 
 	// Determine if the game ended.
-	if parser.game.Playing() == pb.PlayState_GAME_OVER {
+	if parser.game != nil && parser.game.Playing() == pb.PlayState_GAME_OVER {
 		parser.history.PlayState = pb.PlayState_GAME_OVER
 		parser.game.AddFinalScoresToHistory()
 	}
-	// Set challenge rule back to void since we don't know or care what it is.
-	parser.history.ChallengeRule = pb.ChallengeRule_VOID
+	// Set the challenge rule back to the one in the file, which is void
+	// if it wasn't given.
+	parser.history.ChallengeRule = parser.challengeRule
 	return parser.history, nil
 }
 
 // ParseGCG parses a GCG file into a GameHistory.
 func ParseGCG(cfg *config.Config, filename string) (*pb.GameHistory, error) {
+	return ParseGCGWithOptions(cfg, filename, ParseOptions{})
+}
+
+// ParseGCGWithOptions parses a GCG file into a GameHistory with the given
+// options.
+func ParseGCGWithOptions(cfg *config.Config, filename string, opts ParseOptions) (*pb.GameHistory, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
-	return ParseGCGFromReader(cfg, f)
+	defer f.Close()
+	return ParseGCGFromReaderWithOptions(cfg, f, opts)
 }
 
 func writeGCGHeader(s *strings.Builder, h *pb.GameHistory, addlInfo bool) {
@@ -542,6 +803,18 @@ func writeGCGHeader(s *strings.Builder, h *pb.GameHistory, addlInfo bool) {
 		if h.Lexicon != "" {
 			s.WriteString("#lexicon " + h.Lexicon + "\n")
 		}
+		if h.ChallengeRule != pb.ChallengeRule_VOID {
+			s.WriteString("#challenge-rule " + strings.ToLower(h.ChallengeRule.String()) + "\n")
+		}
+		if h.Variant != "" {
+			s.WriteString("#variant " + h.Variant + "\n")
+		}
+		if h.BoardLayout != "" {
+			s.WriteString("#board-layout " + h.BoardLayout + "\n")
+		}
+		if h.LetterDistribution != "" {
+			s.WriteString("#tile-distribution " + h.LetterDistribution + "\n")
+		}
 	}
 	log.Debug().Msg("wrote header")
 }
@@ -590,18 +863,34 @@ func writeEvent(s *strings.Builder, evt *pb.GameEvent) error {
 		fmt.Fprintf(s, ">%v: %v (time) -%d %d\n",
 			nick, rack, evt.LostScore, evt.Cumulative)
 	case pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS:
-		// Treat exactly like a pass, but append a note. The GCG format
-		// does not distinguish between these two cases.
+		// Write it as a pass, marked with a pragma. The GCG format does
+		// not distinguish between these two cases.
 		fmt.Fprintf(s, ">%v: %v - +0 %d\n", nick, rack, evt.Cumulative)
-		fmt.Fprint(s, "#note #unsuccessful-challenge\n")
+		s.WriteString("#unsuccessful-challenge\n")
 
 	default:
 		return fmt.Errorf("event type %v not supported", evtType)
 
 	}
+	if evt.MillisRemaining != nil {
+		s.WriteString("#time " + formatClock(*evt.MillisRemaining) + "\n")
+	}
+	for _, pragma := range evt.UnknownPragmas {
+		s.WriteString(pragma + "\n")
+	}
 	if note != "" {
-		// Note that the note can have line breaks within it ...
-		fmt.Fprintf(s, "#note %v\n", note)
+		// Write every line of the note as a separate #note, so that
+		// nothing in it can be mistaken for another pragma or event.
+		for _, line := range strings.Split(note, "\n") {
+			fmt.Fprintf(s, "#note %v\n", line)
+		}
+	}
+	if evt.Annotation != nil {
+		bts, err := protojson.Marshal(evt.Annotation)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s, "#note %v%s\n", annotationNote, bts)
 	}
 	return nil
 
@@ -625,6 +914,22 @@ func writePlayers(s *strings.Builder, players []*pb.PlayerInfo, flip bool) {
 	}
 }
 
+// writeRacks writes the last known racks, in the order of the players in
+// the GCG.
+func writeRacks(s *strings.Builder, racks []string, flip bool) {
+	if len(racks) != 2 {
+		return
+	}
+	if flip {
+		racks = []string{racks[1], racks[0]}
+	}
+	for i, rack := range racks {
+		if rack != "" {
+			fmt.Fprintf(s, "#rack%d %v\n", i+1, rack)
+		}
+	}
+}
+
 func isPassBeforeEndRackPoints(h *pb.GameHistory, i int) bool {
 	return len(h.Events) > i+1 &&
 		(h.Events[i].Type == pb.GameEvent_PASS ||
@@ -638,6 +943,9 @@ func GameHistoryToGCG(h *pb.GameHistory, addlHeaderInfo bool) (string, error) {
 	var str strings.Builder
 	writeGCGHeader(&str, h, addlHeaderInfo)
 	writePlayers(&str, h.Players, h.SecondWentFirst)
+	for _, pragma := range h.UnknownPragmas {
+		str.WriteString(pragma + "\n")
+	}
 
 	for i, evt := range h.Events {
 		if !isPassBeforeEndRackPoints(h, i) {
@@ -647,6 +955,7 @@ func GameHistoryToGCG(h *pb.GameHistory, addlHeaderInfo bool) (string, error) {
 			}
 		}
 	}
+	writeRacks(&str, h.LastKnownRacks, h.SecondWentFirst)
 
 	return str.String(), nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/domino14/cwgame/config"
 	"github.com/domino14/cwgame/game"
 	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
 	"github.com/domino14/cwgame/move"
 	"github.com/matryer/is"
 	"github.com/stretchr/testify/assert"
@@ -276,3 +277,100 @@ func TestIsBingo(t *testing.T) {
 	assert.True(t, history.Events[0].IsBingo)
 	assert.False(t, history.Events[1].IsBingo)
 }
+
+func TestParseRulePragmas(t *testing.T) {
+	is := is.New(t)
+	gcg := strings.Replace(slurp("./testdata/doug_v_emely_double_challenge.gcg"),
+		"#player2 emely emely\n", `#player2 emely emely
+#challenge-rule double
+#variant CrosswordGame
+#board-layout CrosswordGame
+#tile-distribution english
+`, 1)
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+	is.NoErr(err)
+	is.Equal(h.ChallengeRule, pb.ChallengeRule_DOUBLE)
+	is.Equal(h.Variant, "CrosswordGame")
+	is.Equal(h.BoardLayout, "CrosswordGame")
+	is.Equal(h.LetterDistribution, "english")
+
+	gcgstr, err := GameHistoryToGCG(h, true)
+	is.NoErr(err)
+	is.True(strings.Contains(gcgstr, "#challenge-rule double\n"))
+	is.True(strings.Contains(gcgstr, "#board-layout CrosswordGame\n"))
+	is.True(strings.Contains(gcgstr, "#tile-distribution english\n"))
+
+	// Without the pragma, the challenge rule is not known.
+	h, err = ParseGCG(&DefaultConfig, "./testdata/doug_v_emely_double_challenge.gcg")
+	is.NoErr(err)
+	is.Equal(h.ChallengeRule, pb.ChallengeRule_VOID)
+}
+
+func TestParseBadRulePragmas(t *testing.T) {
+	is := is.New(t)
+	for _, pragma := range []string{"#challenge-rule triple", "#board-layout Tiny",
+		"#tile-distribution klingon"} {
+		gcg := strings.Replace(slurp("./testdata/doug_v_emely.gcg"),
+			"#player2 emely emely\n", "#player2 emely emely\n"+pragma+"\n", 1)
+		_, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+		is.True(err != nil)
+	}
+	gcg := strings.Replace(slurp("./testdata/doug_v_emely.gcg"),
+		"+32 32\n", "+32 32\n#challenge-rule double\n", 1)
+	_, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+	is.Equal(err, errRulesAfterEvents)
+}
+
+func TestParseWithLexicon(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "english")
+	is.NoErr(err)
+	lex, err := lexicon.NewWordList("TINY", rules.LetterDistribution().Alphabet(),
+		[]string{"WINDY"})
+	is.NoErr(err)
+	gcg := strings.Replace(slurp("./testdata/doug_v_emely.gcg"),
+		"#player2 emely emely\n", "#player2 emely emely\n#challenge-rule void\n", 1)
+
+	// GALE is not in the lexicon, so it can't be played with a void
+	// challenge rule.
+	_, err = ParseGCGFromReaderWithOptions(&DefaultConfig, strings.NewReader(gcg),
+		ParseOptions{Lexicon: lex})
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "GALE"))
+
+	withLex := game.NewGameRules(&DefaultConfig, rules.LetterDistribution(), rules.Board(),
+		lex, rules.CrossSetGen())
+	_, err = ParseGCGFromReaderWithOptions(&DefaultConfig, strings.NewReader(gcg),
+		ParseOptions{Rules: withLex})
+	is.True(err != nil)
+
+	// Any word is fine if the lexicon isn't given.
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+	is.NoErr(err)
+	is.Equal(len(h.Events), 28)
+
+	// The lexicon is only used to judge words under a void challenge rule,
+	// and names the lexicon of a game that doesn't have one.
+	h, err = ParseGCGWithOptions(&DefaultConfig, "./testdata/doug_v_emely.gcg",
+		ParseOptions{Lexicon: lex})
+	is.NoErr(err)
+	is.Equal(h.Lexicon, "TINY")
+}
+
+func TestParseMultiLineNote(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+#note a note that carries on
+
+over a blank line
+
+>emely: ADEEGIL 7C GALE +16 16
+#note and another
+`))
+	is.NoErr(err)
+	// Blank lines are only kept inside a note, not after it.
+	is.Equal(h.Events[0].Note, "a note that carries on\n\nover a blank line")
+	is.Equal(h.Events[1].Note, "and another")
+}
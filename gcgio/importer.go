@@ -0,0 +1,171 @@
+package gcgio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/domino14/cwgame/config"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+// ErrUnknownFormat is returned when no importer recognizes a game record.
+var ErrUnknownFormat = errors.New("unrecognized game record format")
+
+// An Importer converts game records in some format into game histories.
+type Importer interface {
+	// Name is a short name for the format, like "json".
+	Name() string
+	// Detect returns true if the data looks like it is in this format.
+	Detect(data []byte) bool
+	// Import converts the data into a game history.
+	Import(cfg *config.Config, data []byte) (*pb.GameHistory, error)
+}
+
+var importers []Importer
+
+func init() {
+	RegisterImporter(jsonImporter{})
+	RegisterImporter(gcgImporter{})
+}
+
+// RegisterImporter adds an importer. Importers are tried in the order they
+// were registered, so the ones with the most specific detectors should be
+// registered first.
+func RegisterImporter(imp Importer) {
+	importers = append(importers, imp)
+}
+
+// FindImporter returns the importer with the given name, or nil if there
+// is none.
+func FindImporter(name string) Importer {
+	for _, imp := range importers {
+		if imp.Name() == name {
+			return imp
+		}
+	}
+	return nil
+}
+
+// Import reads a game record in any format that an importer recognizes.
+func Import(cfg *config.Config, reader io.Reader) (*pb.GameHistory, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	for _, imp := range importers {
+		if imp.Detect(data) {
+			return importWith(cfg, imp, data)
+		}
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ImportAs reads a game record with the importer of the given name.
+func ImportAs(cfg *config.Config, name string, reader io.Reader) (*pb.GameHistory, error) {
+	imp := FindImporter(name)
+	if imp == nil {
+		return nil, fmt.Errorf("there is no importer for %v", name)
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return importWith(cfg, imp, data)
+}
+
+func importWith(cfg *config.Config, imp Importer, data []byte) (*pb.GameHistory, error) {
+	h, err := imp.Import(cfg, data)
+	if err != nil {
+		return nil, fmt.Errorf("importing %v: %w", imp.Name(), err)
+	}
+	return h, nil
+}
+
+// firstLine returns the first line of the data that isn't blank, without
+// surrounding space or a byte order mark.
+func firstLine(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\uFEFF"))
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// jsonImporter reads game histories in the JSON layout of the protobuf
+// messages, either with field names as in the .proto file (as in our
+// testdata) or in camel case. A history wrapped in an object under a
+// "history" key, as some servers return them, is also accepted.
+type jsonImporter struct{}
+
+func (jsonImporter) Name() string {
+	return "json"
+}
+
+func (jsonImporter) Detect(data []byte) bool {
+	return strings.HasPrefix(firstLine(data), "{")
+}
+
+func (jsonImporter) Import(cfg *config.Config, data []byte) (*pb.GameHistory, error) {
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+	if inner, ok := wrapper["history"]; ok && len(wrapper) == 1 {
+		data = inner
+	}
+	h := &pb.GameHistory{}
+	err := protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, h)
+	if err != nil {
+		return nil, err
+	}
+	if len(h.Players) != 2 {
+		return nil, fmt.Errorf("a game needs two players, not %d", len(h.Players))
+	}
+	for i, evt := range h.Events {
+		if evt.Nickname != h.Players[0].Nickname && evt.Nickname != h.Players[1].Nickname {
+			return nil, fmt.Errorf("event %d is by %v, who is not a player", i+1, evt.Nickname)
+		}
+	}
+	return h, nil
+}
+
+// gcgImporter reads GCGs. The parser already allows for Quackle's notes
+// that carry on over several lines; the exchanges of unknown tiles that
+// some programs write are turned down with a clearer error.
+type gcgImporter struct{}
+
+func (gcgImporter) Name() string {
+	return "gcg"
+}
+
+func (gcgImporter) Detect(data []byte) bool {
+	line := firstLine(data)
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, ">")
+}
+
+var unknownExchangeRegex = regexp.MustCompile(`^>\S+:\s+\S*\s+-\d+\s+\+0\s+-?\d+`)
+
+func (gcgImporter) Import(cfg *config.Config, data []byte) (*pb.GameHistory, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		if unknownExchangeRegex.MatchString(strings.TrimSpace(scanner.Text())) {
+			return nil, fmt.Errorf("line %d: exchanges of unknown tiles are not supported", n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ParseGCGFromReader(cfg, bytes.NewReader(data))
+}
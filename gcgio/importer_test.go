@@ -0,0 +1,79 @@
+package gcgio
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+)
+
+func importFile(t *testing.T, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, err = Import(&DefaultConfig, f)
+	return err
+}
+
+func TestImportJSON(t *testing.T) {
+	is := is.New(t)
+	expected, err := ParseGCG(&DefaultConfig, "./testdata/vs_frentz.gcg")
+	is.NoErr(err)
+	// The JSON has the lexicon, which the GCG doesn't.
+	expected.Lexicon = "CSW12"
+	json := slurp("./testdata/vs_frentz.json")
+
+	h, err := Import(&DefaultConfig, strings.NewReader(json))
+	is.NoErr(err)
+	is.True(proto.Equal(h, expected))
+
+	// As some servers send it.
+	h, err = Import(&DefaultConfig, strings.NewReader(`{"history": `+json+"}"))
+	is.NoErr(err)
+	is.True(proto.Equal(h, expected))
+
+	_, err = Import(&DefaultConfig, strings.NewReader(`{"players": [{"nickname": "cesar"}]}`))
+	is.Equal(err.Error(), "importing json: a game needs two players, not 1")
+}
+
+func TestImportQuackleNotes(t *testing.T) {
+	is := is.New(t)
+	h, err := Import(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+#note Quackle carries on notes
+on the next lines.
+
+>emely: ADEEGIL 7C GALE +16 16
+`))
+	is.NoErr(err)
+	is.Equal(h.Events[0].Note, "Quackle carries on notes\non the next lines.")
+	is.Equal(len(h.Events), 2)
+}
+
+func TestImportErrors(t *testing.T) {
+	is := is.New(t)
+	_, err := Import(&DefaultConfig, strings.NewReader("some random text\n"))
+	is.Equal(err, ErrUnknownFormat)
+
+	_, err = Import(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY -7 +0 0
+`))
+	is.Equal(err.Error(), "importing gcg: line 3: exchanges of unknown tiles are not supported")
+
+	_, err = ImportAs(&DefaultConfig, "quackle", strings.NewReader(""))
+	is.Equal(err.Error(), "there is no importer for quackle")
+}
+
+func TestImportTestdata(t *testing.T) {
+	is := is.New(t)
+	for _, f := range []string{"doug_v_emely.gcg", "josh2.json", "vs_andy.json",
+		"noah_vs_mishu.gcg"} {
+		is.NoErr(importFile(t, "./testdata/"+f))
+	}
+}
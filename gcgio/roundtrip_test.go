@@ -0,0 +1,185 @@
+package gcgio
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+// roundTrip writes out a history as a GCG and parses it again.
+func roundTrip(t *testing.T, h *pb.GameHistory) *pb.GameHistory {
+	gcg, err := GameHistoryToGCG(h, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+	if err != nil {
+		t.Fatalf("%v\n%v", err, gcg)
+	}
+	return h2
+}
+
+func TestRoundTripTestdata(t *testing.T) {
+	files, err := filepath.Glob("./testdata/*.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// These are meant not to parse.
+	unparseable := map[string]bool{
+		"name_weird_encoding_with_header.gcg": true,
+	}
+	for _, f := range files {
+		t.Run(filepath.Base(f), func(t *testing.T) {
+			is := is.New(t)
+			if unparseable[filepath.Base(f)] {
+				t.Skip("does not parse")
+			}
+			h, err := ParseGCG(&DefaultConfig, f)
+			if err != nil {
+				t.Fatalf("does not parse: %v", err)
+			}
+			// Without the defaults, nothing is added that the file
+			// didn't have.
+			bare, err := ParseGCGWithOptions(&DefaultConfig, f, ParseOptions{NoDefaults: true})
+			is.NoErr(err)
+			gcg, err := GameHistoryToGCG(bare, true)
+			is.NoErr(err)
+			for _, pragma := range []string{"#lexicon", "#variant"} {
+				is.Equal(strings.Contains(gcg, pragma), strings.Contains(h.OriginalGcg, pragma))
+			}
+			h2 := roundTrip(t, h)
+			// A pass just before the end rack points is left out of a
+			// GCG, as the format implies it.
+			for i := len(h.Events) - 1; i >= 0; i-- {
+				if isPassBeforeEndRackPoints(h, i) {
+					h.Events = append(h.Events[:i], h.Events[i+1:]...)
+				}
+			}
+			// Otherwise, the original GCG is the only thing that should
+			// differ.
+			h.OriginalGcg, h2.OriginalGcg = "", ""
+			is.True(proto.Equal(h, h2))
+		})
+	}
+}
+
+func TestRoundTripPragmasAndNotes(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+#tournament-game yes
+#note about this game
+>doug: DINNVWY 8D WINDY +32 32
+#annotator-rating 9
+#note a note
+#note
+#note with #player1 some lines > and >emely: AB 8A AB +5 5
+>emely: ADEEGIL 7C GALE +16 16
+#rack2 ADEEIL
+#rack1 AEJNOSV
+`))
+	is.NoErr(err)
+	is.Equal(h.UnknownPragmas, []string{"#tournament-game yes", "#note about this game"})
+	is.Equal(h.Events[0].UnknownPragmas, []string{"#annotator-rating 9"})
+	is.Equal(h.Events[0].Note, "a note\n\nwith #player1 some lines > and >emely: AB 8A AB +5 5")
+	is.Equal(h.LastKnownRacks, []string{"AEJNOSV", "ADEEIL"})
+
+	h2 := roundTrip(t, h)
+	h.OriginalGcg, h2.OriginalGcg = "", ""
+	is.True(proto.Equal(h, h2))
+}
+
+func TestRoundTripHeaderNoteWithContinuation(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+#note about this game
+and more about it
+>doug: DINNVWY 8D WINDY +32 32
+`))
+	is.NoErr(err)
+	is.Equal(h.UnknownPragmas, []string{"#note about this game\nand more about it"})
+	is.Equal(h.Events[0].Note, "")
+
+	h2 := roundTrip(t, h)
+	h.OriginalGcg, h2.OriginalGcg = "", ""
+	is.True(proto.Equal(h, h2))
+}
+
+func TestRoundTripUnsuccessfulChallenge(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+>emely: ADEEGIL - +0 0
+#unsuccessful-challenge
+#note lost a turn
+>doug: AEJNOSV - +0 32
+#note #unsuccessful-challenge
+#note also lost
+>emely: ADEEGIL - +0 0
+#note a pass
+#note #unsuccessful-challenge
+`))
+	is.NoErr(err)
+	is.Equal(h.Events[1].Type, pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS)
+	is.Equal(h.Events[1].Note, "lost a turn")
+	// The note that older versions wrote is still read.
+	is.Equal(h.Events[2].Type, pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS)
+	is.Equal(h.Events[2].Note, "also lost")
+	// It only counts before any other note.
+	is.Equal(h.Events[3].Type, pb.GameEvent_PASS)
+	is.Equal(h.Events[3].Note, "a pass\n#unsuccessful-challenge")
+
+	h2 := roundTrip(t, h)
+	h.OriginalGcg, h2.OriginalGcg = "", ""
+	is.True(proto.Equal(h, h2))
+
+	_, err = ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+#unsuccessful-challenge
+`))
+	is.True(err != nil)
+}
+
+func TestRoundTripAnnotation(t *testing.T) {
+	is := is.New(t)
+	h, err := ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+#note a good start
+>emely: ADEEGIL 7C GALE +16 16
+#note @annotation {oops
+`))
+	is.NoErr(err)
+	is.Equal(h.Events[1].Note, "@annotation {oops")
+	is.True(h.Events[1].Annotation == nil)
+
+	h.Events[0].Annotation = &pb.Annotation{
+		Candidates: []*pb.CandidatePlay{
+			{Description: "8D WINDY", Score: 32, Leave: "N", Equity: 35.5, WinPct: proto.Float64(0.6)},
+			{Description: "8H WINDY", Score: 30, Leave: "N", Equity: 33.5},
+		},
+		Tags: []string{"best move"},
+		Variations: []*pb.Variation{{
+			Name: "what if",
+			Events: []*pb.GameEvent{{Nickname: "doug", Rack: "DINNVWY",
+				Type: pb.GameEvent_EXCHANGE, Exchanged: "V", Note: "two\nlines"}},
+		}},
+	}
+	gcg, err := GameHistoryToGCG(h, false)
+	is.NoErr(err)
+	is.True(strings.Contains(gcg, "#note a good start\n#note @annotation {"))
+
+	h2 := roundTrip(t, h)
+	h.OriginalGcg, h2.OriginalGcg = "", ""
+	is.True(proto.Equal(h, h2))
+	is.Equal(h2.Events[0].Note, "a good start")
+}
@@ -0,0 +1,240 @@
+package gcgio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+// A Diagnostic is a problem found at a position in a GCG. Lines and
+// columns start at 1.
+type Diagnostic struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", d.Line, d.Column, d.Err)
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics are all of the problems found in a GCG in strict mode.
+type Diagnostics []*Diagnostic
+
+func (ds Diagnostics) Error() string {
+	msgs := make([]string, len(ds))
+	for i, d := range ds {
+		msgs[i] = d.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// diagnostic creates a diagnostic pointing at a submatch of the current
+// line. Submatch 0 is the whole match.
+func (p *parser) diagnostic(submatch int, err error) *Diagnostic {
+	col := 1
+	if 2*submatch < len(p.submatches) && p.submatches[2*submatch] >= 0 {
+		col = utf8.RuneCountInString(p.line[:p.submatches[2*submatch]]) + 1
+	}
+	return &Diagnostic{Line: p.lineNum, Column: col, Err: err}
+}
+
+// report adds a problem that does not stop the game from being followed.
+func (p *parser) report(submatch int, format string, a ...interface{}) {
+	p.diagnostics = append(p.diagnostics, p.diagnostic(submatch, fmt.Errorf(format, a...)))
+}
+
+// The submatches of the event regexes that diagnostics point to.
+const (
+	rackSubmatch     = 2
+	playSubmatch     = 4
+	exchangeSubmatch = 3
+)
+
+// scoreSubmatch is the submatch with the score, bonus or penalty of an
+// event.
+func scoreSubmatch(evt *pb.GameEvent) int {
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE:
+		return 5
+	case pb.GameEvent_END_RACK_PENALTY:
+		return 4
+	}
+	return 3
+}
+
+// cumulativeSubmatch is the submatch with the cumulative score, which is
+// always the last one.
+func (p *parser) cumulativeSubmatch() int {
+	return len(p.submatches)/2 - 1
+}
+
+// scoreDelta is how much an event changes its player's cumulative score.
+func scoreDelta(evt *pb.GameEvent) int32 {
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE:
+		return evt.Score
+	case pb.GameEvent_CHALLENGE_BONUS:
+		return evt.Bonus
+	case pb.GameEvent_END_RACK_PTS:
+		return evt.EndRackPoints
+	case pb.GameEvent_PHONY_TILES_RETURNED, pb.GameEvent_TIME_PENALTY,
+		pb.GameEvent_END_RACK_PENALTY:
+		return -evt.LostScore
+	}
+	return 0
+}
+
+// contains returns true if every tile in sub is also in tiles.
+func contains(tiles, sub alphabet.MachineWord) bool {
+	_, err := game.Leave(tiles, sub)
+	return err == nil
+}
+
+// sorted returns the tiles in alphabet order, so that they are always
+// reported the same way.
+func sorted(tiles alphabet.MachineWord) alphabet.MachineWord {
+	sorted := make(alphabet.MachineWord, len(tiles))
+	copy(sorted, tiles)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func sameTiles(a, b alphabet.MachineWord) bool {
+	return len(a) == len(b) && contains(a, b)
+}
+
+func (p *parser) opponent(nick string) string {
+	for _, player := range p.history.Players {
+		if player.Nickname != nick {
+			return player.Nickname
+		}
+	}
+	return ""
+}
+
+// unseen returns all of the tiles that are not on the board.
+func (p *parser) unseen() alphabet.MachineWord {
+	g := p.game
+	tiles := alphabet.MachineWord(g.Bag().Peek())
+	tiles = append(tiles, g.RackFor(0).TilesOn()...)
+	return append(tiles, g.RackFor(1).TilesOn()...)
+}
+
+// checkEvent checks an event against the game before it is played. Most
+// problems are reported and checking carries on; an error is returned if
+// the event can't be played at all.
+func (p *parser) checkEvent(evt *pb.GameEvent) error {
+	g := p.game
+	alph := g.Alphabet()
+	ld := g.Bag().LetterDistribution()
+
+	// A total is right if it follows on from the last total in the file,
+	// or from the scores written so far, so that one mistake is reported
+	// once whether it was carried forward or not.
+	delta := scoreDelta(evt)
+	expected := p.cumes[evt.Nickname] + delta
+	p.totals[evt.Nickname] += delta
+	if evt.Cumulative != expected && evt.Cumulative != p.totals[evt.Nickname] {
+		p.report(p.cumulativeSubmatch(), "cumulative score is %d, but should be %d",
+			evt.Cumulative, expected)
+	}
+	p.cumes[evt.Nickname] = evt.Cumulative
+
+	rack, err := alphabet.ToMachineWord(evt.Rack, alph)
+	if err != nil {
+		return p.diagnostic(rackSubmatch, err)
+	}
+
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE, pb.GameEvent_EXCHANGE, pb.GameEvent_PASS:
+		if len(rack) > game.RackTileLimit {
+			p.report(rackSubmatch, "rack %v has more than %d tiles", evt.Rack, game.RackTileLimit)
+		}
+		if _, err := game.Leave(p.unseen(), rack); err != nil {
+			return p.diagnostic(rackSubmatch, fmt.Errorf(
+				"rack %v has tiles that are not in the bag or on a rack", evt.Rack))
+		}
+		if kept, ok := p.kept[evt.Nickname]; ok && !contains(rack, kept) {
+			p.report(rackSubmatch, "rack %v does not have the tiles %v kept from the last turn",
+				evt.Rack, kept.UserVisible(alph))
+		}
+	}
+
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE:
+		m, err := g.CreateAndScorePlacementMove(evt.Position, evt.PlayedTiles, evt.Rack)
+		if err != nil {
+			return p.diagnostic(playSubmatch, err)
+		}
+		if m.Score() != int(evt.Score) {
+			p.report(scoreSubmatch(evt), "%v %v scores %d, not %d", evt.Position,
+				evt.PlayedTiles, m.Score(), evt.Score)
+		}
+		p.kept[evt.Nickname] = sorted(m.Leave())
+
+	case pb.GameEvent_EXCHANGE:
+		exchanged, err := alphabet.ToMachineWord(evt.Exchanged, alph)
+		if err != nil {
+			return p.diagnostic(exchangeSubmatch, err)
+		}
+		leave, err := game.Leave(rack, exchanged)
+		if err != nil {
+			return p.diagnostic(exchangeSubmatch, fmt.Errorf(
+				"exchanged tiles %v are not all on rack %v", evt.Exchanged, evt.Rack))
+		}
+		if g.Bag().TilesRemaining() < game.ExchangeLimit {
+			p.report(exchangeSubmatch, "cannot exchange with %d tiles in the bag",
+				g.Bag().TilesRemaining())
+		}
+		p.kept[evt.Nickname] = sorted(leave)
+
+	case pb.GameEvent_PASS:
+		p.kept[evt.Nickname] = sorted(rack)
+
+	case pb.GameEvent_PHONY_TILES_RETURNED:
+		last := p.history.Events[len(p.history.Events)-1]
+		if last.Type != pb.GameEvent_TILE_PLACEMENT_MOVE || last.Nickname != evt.Nickname {
+			return p.diagnostic(0, fmt.Errorf("the last event was not a play by %v", evt.Nickname))
+		}
+		if evt.LostScore != last.Score {
+			p.report(scoreSubmatch(evt), "the play scored %d, not %d", last.Score, evt.LostScore)
+		}
+		lastRack, err := alphabet.ToMachineWord(last.Rack, alph)
+		if err == nil && !sameTiles(rack, lastRack) {
+			p.report(rackSubmatch, "rack %v is not the rack %v of the play", evt.Rack, last.Rack)
+		}
+		p.kept[evt.Nickname] = sorted(rack)
+
+	case pb.GameEvent_END_RACK_PTS:
+		if pts := 2 * rack.Score(ld); int(evt.EndRackPoints) != pts {
+			p.report(scoreSubmatch(evt), "rack %v is worth %d points, not %d", evt.Rack,
+				pts, evt.EndRackPoints)
+		}
+		opp := p.opponent(evt.Nickname)
+		if kept, ok := p.kept[opp]; ok && !contains(rack, kept) {
+			p.report(rackSubmatch, "rack %v does not have the tiles %v that %v kept",
+				evt.Rack, kept.UserVisible(alph), opp)
+		}
+
+	case pb.GameEvent_END_RACK_PENALTY:
+		if pts := rack.Score(ld); int(evt.LostScore) != pts {
+			p.report(scoreSubmatch(evt), "rack %v is worth %d points, not %d", evt.Rack,
+				pts, evt.LostScore)
+		}
+		if kept, ok := p.kept[evt.Nickname]; ok && !contains(rack, kept) {
+			p.report(rackSubmatch, "rack %v does not have the tiles %v kept from the last turn",
+				evt.Rack, kept.UserVisible(alph))
+		}
+	}
+	return nil
+}
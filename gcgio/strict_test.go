@@ -0,0 +1,122 @@
+package gcgio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseStrict(gcg string) error {
+	_, err := ParseGCGFromReaderWithOptions(&DefaultConfig, strings.NewReader(gcg),
+		ParseOptions{Strict: true})
+	return err
+}
+
+func TestStrictValidGame(t *testing.T) {
+	history, err := ParseGCGWithOptions(&DefaultConfig, "./testdata/doug_v_emely.gcg",
+		ParseOptions{Strict: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 28, len(history.Events))
+}
+
+func TestStrictScoresAndCumulatives(t *testing.T) {
+	err := parseStrict(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +30 30
+>emely: ADEEGIL 7C GALE +16 17
+>doug: AEJNOSV E3 JAVE..N +34 64
+`)
+	// The cumulative scores on the first and last lines follow on from
+	// the scores written, so only the second line is wrong.
+	assert.Equal(t, `line 4, column 26: 8D WINDY scores 32, not 30
+line 5, column 29: cumulative score is 17, but should be 16`, err.Error())
+
+	// A total that is mistyped once is reported once, whether the next
+	// total carries the mistake forward or not.
+	for _, last := range []string{"66", "57"} {
+		err = parseStrict(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 23
+>emely: ADEEGIL 7C GALE +16 16
+>doug: AEJNOSV E3 JAVE..N +34 ` + last + `
+`)
+		assert.Equal(t, "line 4, column 29: cumulative score is 23, but should be 32", err.Error())
+	}
+}
+
+func TestStrictRacks(t *testing.T) {
+	err := parseStrict(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+>emely: ADEEGIL -AEQ +0 0
+>doug: AEJNOSZ E3 JAVE..N +34 66
+`)
+	ds, ok := err.(Diagnostics)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(ds))
+	// The rest of the game can't be followed after a bad exchange.
+	assert.Equal(t, "line 5, column 18: exchanged tiles AEQ are not all on rack ADEEGIL",
+		ds[0].Error())
+
+	err = parseStrict(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+>emely: ADEEGIL 7C GALE +16 16
+>doug: AEJOSVZ E3 JAVE..N +34 66
+`)
+	assert.Equal(t, `line 6, column 8: rack AEJOSVZ does not have the tiles NV kept from the last turn
+line 6, column 19: Tile in play but not in rack: N 0`, err.Error())
+}
+
+func TestStrictUnseenTiles(t *testing.T) {
+	err := parseStrict(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+>doug: ZZ 8G ZZ +40 40
+`)
+	assert.Equal(t, "line 4, column 8: rack ZZ has tiles that are not in the bag or on a rack",
+		err.Error())
+}
+
+func TestStrictEndRackPoints(t *testing.T) {
+	gcg := slurp("./testdata/doug_v_emely.gcg")
+	gcg = strings.Replace(gcg, "(OPEG) +14 345", "(OPEG) +12 343", 1)
+	err := parseStrict(gcg)
+	ds := err.(Diagnostics)
+	assert.Equal(t, 1, len(ds))
+	d := ds[0]
+	assert.Equal(t, 30, d.Line)
+	assert.Equal(t, 18, d.Column)
+	assert.Equal(t, "rack OPEG is worth 14 points, not 12", d.Err.Error())
+}
+
+func TestStrictStructuralErrors(t *testing.T) {
+	err := parseStrict(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +32 32
+what is this
+#lexicon CSW19
+>emely: ADEEGIL 7C GALE +16 16
+`)
+	ds := err.(Diagnostics)
+	assert.Equal(t, 2, len(ds))
+	assert.Equal(t, 5, ds[0].Line)
+	assert.Equal(t, 6, ds[1].Line)
+	assert.True(t, errors.Is(ds[1], errPragmaPrecedeEvent))
+
+	// Lenient mode stops at the first problem, as it always has.
+	_, err = ParseGCGFromReader(&DefaultConfig, strings.NewReader(`#character-encoding UTF-8
+#player1 doug doug
+#player2 emely emely
+>doug: DINNVWY 8D WINDY +30 30
+#lexicon CSW19
+`))
+	assert.Equal(t, errPragmaPrecedeEvent, err)
+}
@@ -284,6 +284,15 @@ type GameHistory struct {
 	// highest score, because there can be timeouts, etc. If it's a tie,
 	// it will be a -1.
 	Winner int32 `protobuf:"varint,16,opt,name=winner,proto3" json:"winner,omitempty"`
+	// Pragmas in the header of a GCG that are not understood. They are kept
+	// verbatim so that the GCG can be written out again without losing them.
+	UnknownPragmas []string `protobuf:"bytes,17,rep,name=unknown_pragmas,json=unknownPragmas,proto3" json:"unknown_pragmas,omitempty"`
+	// The name of the board layout (see board.Layouts). If it is not set, the
+	// layout comes from the variant.
+	BoardLayout string `protobuf:"bytes,18,opt,name=board_layout,json=boardLayout,proto3" json:"board_layout,omitempty"`
+	// The name of the letter distribution. If it is not set, it is determined
+	// from the lexicon.
+	LetterDistribution string `protobuf:"bytes,19,opt,name=letter_distribution,json=letterDistribution,proto3" json:"letter_distribution,omitempty"`
 }
 
 func (x *GameHistory) Reset() {
@@ -430,6 +439,27 @@ func (x *GameHistory) GetWinner() int32 {
 	return 0
 }
 
+func (x *GameHistory) GetUnknownPragmas() []string {
+	if x != nil {
+		return x.UnknownPragmas
+	}
+	return nil
+}
+
+func (x *GameHistory) GetBoardLayout() string {
+	if x != nil {
+		return x.BoardLayout
+	}
+	return ""
+}
+
+func (x *GameHistory) GetLetterDistribution() string {
+	if x != nil {
+		return x.LetterDistribution
+	}
+	return ""
+}
+
 // This should be merged into Move.
 type GameEvent struct {
 	state         protoimpl.MessageState
@@ -456,8 +486,13 @@ type GameEvent struct {
 	// words_formed is a list of all words made by this play, in user-visible
 	// pretty form. The first word is the "main" word, anything after it are
 	// cross-words.
-	WordsFormed     []string `protobuf:"bytes,17,rep,name=words_formed,json=wordsFormed,proto3" json:"words_formed,omitempty"`
-	MillisRemaining int32    `protobuf:"varint,18,opt,name=millis_remaining,json=millisRemaining,proto3" json:"millis_remaining,omitempty"`
+	WordsFormed []string `protobuf:"bytes,17,rep,name=words_formed,json=wordsFormed,proto3" json:"words_formed,omitempty"`
+	// The time the player has left after this event, if a clock is kept.
+	MillisRemaining *int32 `protobuf:"varint,18,opt,name=millis_remaining,json=millisRemaining,proto3,oneof" json:"millis_remaining,omitempty"`
+	// GCG pragmas following this event that are not understood, verbatim.
+	UnknownPragmas []string `protobuf:"bytes,19,rep,name=unknown_pragmas,json=unknownPragmas,proto3" json:"unknown_pragmas,omitempty"`
+	// Structured analysis of this turn, as opposed to the free-form note.
+	Annotation *Annotation `protobuf:"bytes,20,opt,name=annotation,proto3" json:"annotation,omitempty"`
 }
 
 func (x *GameEvent) Reset() {
@@ -612,12 +647,232 @@ func (x *GameEvent) GetWordsFormed() []string {
 }
 
 func (x *GameEvent) GetMillisRemaining() int32 {
+	if x != nil && x.MillisRemaining != nil {
+		return *x.MillisRemaining
+	}
+	return 0
+}
+
+func (x *GameEvent) GetUnknownPragmas() []string {
 	if x != nil {
-		return x.MillisRemaining
+		return x.UnknownPragmas
+	}
+	return nil
+}
+
+func (x *GameEvent) GetAnnotation() *Annotation {
+	if x != nil {
+		return x.Annotation
+	}
+	return nil
+}
+
+// An Annotation is the analysis of one turn.
+type Annotation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Moves that could have been made instead, usually best first.
+	Candidates []*CandidatePlay `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	// Short labels for the turn, such as "missed bingo" or "phony".
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Lines of play that branch off from this turn.
+	Variations []*Variation `protobuf:"bytes,3,rep,name=variations,proto3" json:"variations,omitempty"`
+}
+
+func (x *Annotation) Reset() {
+	*x = Annotation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Annotation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Annotation) ProtoMessage() {}
+
+func (x *Annotation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Annotation.ProtoReflect.Descriptor instead.
+func (*Annotation) Descriptor() ([]byte, []int) {
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Annotation) GetCandidates() []*CandidatePlay {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *Annotation) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Annotation) GetVariations() []*Variation {
+	if x != nil {
+		return x.Variations
+	}
+	return nil
+}
+
+// A CandidatePlay is a move that could have been made at a turn.
+type CandidatePlay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The move as it is usually shown, such as "8D WINDY" or "(exch AEI)".
+	Description string  `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Score       int32   `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	Leave       string  `protobuf:"bytes,3,opt,name=leave,proto3" json:"leave,omitempty"`
+	Equity      float64 `protobuf:"fixed64,4,opt,name=equity,proto3" json:"equity,omitempty"`
+	// The chance of winning after the move, from 0 to 1, if it is known.
+	WinPct *float64 `protobuf:"fixed64,5,opt,name=win_pct,json=winPct,proto3,oneof" json:"win_pct,omitempty"`
+}
+
+func (x *CandidatePlay) Reset() {
+	*x = CandidatePlay{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CandidatePlay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandidatePlay) ProtoMessage() {}
+
+func (x *CandidatePlay) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandidatePlay.ProtoReflect.Descriptor instead.
+func (*CandidatePlay) Descriptor() ([]byte, []int) {
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CandidatePlay) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CandidatePlay) GetScore() int32 {
+	if x != nil {
+		return x.Score
 	}
 	return 0
 }
 
+func (x *CandidatePlay) GetLeave() string {
+	if x != nil {
+		return x.Leave
+	}
+	return ""
+}
+
+func (x *CandidatePlay) GetEquity() float64 {
+	if x != nil {
+		return x.Equity
+	}
+	return 0
+}
+
+func (x *CandidatePlay) GetWinPct() float64 {
+	if x != nil && x.WinPct != nil {
+		return *x.WinPct
+	}
+	return 0
+}
+
+// A Variation is a line of play that replaces the turn it is attached to
+// and the turns after it.
+type Variation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Events []*GameEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *Variation) Reset() {
+	*x = Variation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Variation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variation) ProtoMessage() {}
+
+func (x *Variation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variation.ProtoReflect.Descriptor instead.
+func (*Variation) Descriptor() ([]byte, []int) {
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Variation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Variation) GetEvents() []*GameEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
 type PlayerInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -633,7 +888,7 @@ type PlayerInfo struct {
 func (x *PlayerInfo) Reset() {
 	*x = PlayerInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_cwgame_cwgame_proto_msgTypes[2]
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -646,7 +901,7 @@ func (x *PlayerInfo) String() string {
 func (*PlayerInfo) ProtoMessage() {}
 
 func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cwgame_cwgame_proto_msgTypes[2]
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -659,7 +914,7 @@ func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PlayerInfo.ProtoReflect.Descriptor instead.
 func (*PlayerInfo) Descriptor() ([]byte, []int) {
-	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{2}
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *PlayerInfo) GetNickname() string {
@@ -694,7 +949,7 @@ type BotRequest struct {
 func (x *BotRequest) Reset() {
 	*x = BotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_cwgame_cwgame_proto_msgTypes[3]
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -707,7 +962,7 @@ func (x *BotRequest) String() string {
 func (*BotRequest) ProtoMessage() {}
 
 func (x *BotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cwgame_cwgame_proto_msgTypes[3]
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -720,7 +975,7 @@ func (x *BotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BotRequest.ProtoReflect.Descriptor instead.
 func (*BotRequest) Descriptor() ([]byte, []int) {
-	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{3}
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BotRequest) GetGameHistory() *GameHistory {
@@ -744,7 +999,7 @@ type BotResponse struct {
 func (x *BotResponse) Reset() {
 	*x = BotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_cwgame_cwgame_proto_msgTypes[4]
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -757,7 +1012,7 @@ func (x *BotResponse) String() string {
 func (*BotResponse) ProtoMessage() {}
 
 func (x *BotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cwgame_cwgame_proto_msgTypes[4]
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -770,7 +1025,7 @@ func (x *BotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BotResponse.ProtoReflect.Descriptor instead.
 func (*BotResponse) Descriptor() ([]byte, []int) {
-	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{4}
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{7}
 }
 
 func (m *BotResponse) GetResponse() isBotResponse_Response {
@@ -810,12 +1065,278 @@ func (*BotResponse_Move) isBotResponse_Response() {}
 
 func (*BotResponse_Error) isBotResponse_Response() {}
 
+// NewGameRequest contains everything needed to start a brand new game.
+type NewGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Players []*PlayerInfo `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Lexicon string        `protobuf:"bytes,2,opt,name=lexicon,proto3" json:"lexicon,omitempty"`
+	// The variant, together with the lexicon, determines the board
+	// configuration and letter distribution. See game.HistoryToVariant.
+	Variant       string        `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`
+	ChallengeRule ChallengeRule `protobuf:"varint,4,opt,name=challenge_rule,json=challengeRule,proto3,enum=cwgame.ChallengeRule" json:"challenge_rule,omitempty"`
+	// If going_first is -1, the first player is determined randomly.
+	// Otherwise it is the index (in `players`) of the player who goes first.
+	GoingFirst int32 `protobuf:"varint,5,opt,name=going_first,json=goingFirst,proto3" json:"going_first,omitempty"`
+}
+
+func (x *NewGameRequest) Reset() {
+	*x = NewGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewGameRequest) ProtoMessage() {}
+
+func (x *NewGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewGameRequest.ProtoReflect.Descriptor instead.
+func (*NewGameRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NewGameRequest) GetPlayers() []*PlayerInfo {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *NewGameRequest) GetLexicon() string {
+	if x != nil {
+		return x.Lexicon
+	}
+	return ""
+}
+
+func (x *NewGameRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *NewGameRequest) GetChallengeRule() ChallengeRule {
+	if x != nil {
+		return x.ChallengeRule
+	}
+	return ChallengeRule_VOID
+}
+
+func (x *NewGameRequest) GetGoingFirst() int32 {
+	if x != nil {
+		return x.GoingFirst
+	}
+	return 0
+}
+
+type GameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId string `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+}
+
+func (x *GameRequest) Reset() {
+	*x = GameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameRequest) ProtoMessage() {}
+
+func (x *GameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameRequest.ProtoReflect.Descriptor instead.
+func (*GameRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GameRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+// SubmitMoveRequest submits a move for the player on turn. The event only
+// needs its type and, depending on the type, its position and played_tiles
+// (for TILE_PLACEMENT_MOVE) or exchanged tiles (for EXCHANGE). A CHALLENGE
+// event challenges the last play. The nickname must match the player whose
+// turn it is (or, for a CHALLENGE, the player challenging).
+type SubmitMoveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId          string     `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Event           *GameEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	MillisRemaining int32      `protobuf:"varint,3,opt,name=millis_remaining,json=millisRemaining,proto3" json:"millis_remaining,omitempty"`
+}
+
+func (x *SubmitMoveRequest) Reset() {
+	*x = SubmitMoveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitMoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitMoveRequest) ProtoMessage() {}
+
+func (x *SubmitMoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitMoveRequest.ProtoReflect.Descriptor instead.
+func (*SubmitMoveRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubmitMoveRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *SubmitMoveRequest) GetEvent() *GameEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *SubmitMoveRequest) GetMillisRemaining() int32 {
+	if x != nil {
+		return x.MillisRemaining
+	}
+	return 0
+}
+
+// SubmitMoveResponse contains the events that were added to the history
+// by the submitted move, in order. A single move may add several events;
+// for example, a play that goes out may also add an END_RACK_PTS event.
+type SubmitMoveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events    []*GameEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	PlayState PlayState    `protobuf:"varint,2,opt,name=play_state,json=playState,proto3,enum=cwgame.PlayState" json:"play_state,omitempty"`
+	// The rack of the player who just moved, after drawing.
+	Rack string `protobuf:"bytes,3,opt,name=rack,proto3" json:"rack,omitempty"`
+}
+
+func (x *SubmitMoveResponse) Reset() {
+	*x = SubmitMoveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_cwgame_cwgame_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitMoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitMoveResponse) ProtoMessage() {}
+
+func (x *SubmitMoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cwgame_cwgame_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitMoveResponse.ProtoReflect.Descriptor instead.
+func (*SubmitMoveResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cwgame_cwgame_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SubmitMoveResponse) GetEvents() []*GameEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *SubmitMoveResponse) GetPlayState() PlayState {
+	if x != nil {
+		return x.PlayState
+	}
+	return PlayState_PLAYING
+}
+
+func (x *SubmitMoveResponse) GetRack() string {
+	if x != nil {
+		return x.Rack
+	}
+	return ""
+}
+
 var File_proto_cwgame_cwgame_proto protoreflect.FileDescriptor
 
 var file_proto_cwgame_cwgame_proto_rawDesc = []byte{
 	0x0a, 0x19, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2f, 0x63,
 	0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x63, 0x77, 0x67,
-	0x61, 0x6d, 0x65, 0x22, 0xbb, 0x04, 0x0a, 0x0b, 0x47, 0x61, 0x6d, 0x65, 0x48, 0x69, 0x73, 0x74,
+	0x61, 0x6d, 0x65, 0x22, 0xb8, 0x05, 0x0a, 0x0b, 0x47, 0x61, 0x6d, 0x65, 0x48, 0x69, 0x73, 0x74,
 	0x6f, 0x72, 0x79, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20,
 	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d,
 	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2c,
@@ -851,87 +1372,179 @@ var file_proto_cwgame_cwgame_proto_rawDesc = []byte{
 	0x18, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e,
 	0x6e, 0x65, 0x72, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65,
-	0x72, 0x22, 0xbc, 0x06, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
-	0x1a, 0x0a, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x6f, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x74, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72,
-	0x61, 0x63, 0x6b, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x16, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
-	0x1e, 0x0a, 0x0a, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0a, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x12,
-	0x10, 0x0a, 0x03, 0x72, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x72, 0x6f,
-	0x77, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x39, 0x0a, 0x09, 0x64, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63,
-	0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e,
-	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x21, 0x0a, 0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6c, 0x65, 0x73,
-	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x54, 0x69,
-	0x6c, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
-	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
-	0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x6f, 0x6e, 0x75, 0x73,
-	0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x62, 0x6f, 0x6e, 0x75, 0x73, 0x12, 0x26, 0x0a,
-	0x0f, 0x65, 0x6e, 0x64, 0x5f, 0x72, 0x61, 0x63, 0x6b, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
-	0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x65, 0x6e, 0x64, 0x52, 0x61, 0x63, 0x6b, 0x50,
-	0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f, 0x73, 0x74, 0x5f, 0x73, 0x63,
-	0x6f, 0x72, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x6f, 0x73, 0x74, 0x53,
-	0x63, 0x6f, 0x72, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x62, 0x69, 0x6e, 0x67, 0x6f,
-	0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x12,
-	0x21, 0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x18,
-	0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x46, 0x6f, 0x72, 0x6d,
-	0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x6d,
-	0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x6d, 0x69,
-	0x6c, 0x6c, 0x69, 0x73, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0xd5, 0x01,
-	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x54, 0x49, 0x4c, 0x45, 0x5f, 0x50,
-	0x4c, 0x41, 0x43, 0x45, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x00, 0x12,
-	0x18, 0x0a, 0x14, 0x50, 0x48, 0x4f, 0x4e, 0x59, 0x5f, 0x54, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x52,
-	0x45, 0x54, 0x55, 0x52, 0x4e, 0x45, 0x44, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x41, 0x53,
-	0x53, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x48, 0x41, 0x4c, 0x4c, 0x45, 0x4e, 0x47, 0x45,
-	0x5f, 0x42, 0x4f, 0x4e, 0x55, 0x53, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x58, 0x43, 0x48,
-	0x41, 0x4e, 0x47, 0x45, 0x10, 0x04, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x4e, 0x44, 0x5f, 0x52, 0x41,
-	0x43, 0x4b, 0x5f, 0x50, 0x54, 0x53, 0x10, 0x05, 0x12, 0x10, 0x0a, 0x0c, 0x54, 0x49, 0x4d, 0x45,
-	0x5f, 0x50, 0x45, 0x4e, 0x41, 0x4c, 0x54, 0x59, 0x10, 0x06, 0x12, 0x14, 0x0a, 0x10, 0x45, 0x4e,
-	0x44, 0x5f, 0x52, 0x41, 0x43, 0x4b, 0x5f, 0x50, 0x45, 0x4e, 0x41, 0x4c, 0x54, 0x59, 0x10, 0x07,
-	0x12, 0x24, 0x0a, 0x20, 0x55, 0x4e, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x46, 0x55, 0x4c,
-	0x5f, 0x43, 0x48, 0x41, 0x4c, 0x4c, 0x45, 0x4e, 0x47, 0x45, 0x5f, 0x54, 0x55, 0x52, 0x4e, 0x5f,
-	0x4c, 0x4f, 0x53, 0x53, 0x10, 0x08, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x48, 0x41, 0x4c, 0x4c, 0x45,
-	0x4e, 0x47, 0x45, 0x10, 0x09, 0x22, 0x29, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x0a, 0x48, 0x4f, 0x52, 0x49, 0x5a, 0x4f, 0x4e, 0x54, 0x41, 0x4c,
-	0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x56, 0x45, 0x52, 0x54, 0x49, 0x43, 0x41, 0x4c, 0x10, 0x01,
-	0x22, 0x5e, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a,
-	0x0a, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65,
-	0x61, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72,
-	0x65, 0x61, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f,
-	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
-	0x22, 0x44, 0x0a, 0x0a, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x36,
-	0x0a, 0x0c, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61,
-	0x6d, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x0b, 0x67, 0x61, 0x6d, 0x65, 0x48,
-	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x5a, 0x0a, 0x0b, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d,
-	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x16,
-	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
-	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x2a, 0x43, 0x0a, 0x09, 0x50, 0x6c, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
-	0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41, 0x59, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16,
-	0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x5f, 0x46, 0x49, 0x4e, 0x41,
-	0x4c, 0x5f, 0x50, 0x41, 0x53, 0x53, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x47, 0x41, 0x4d, 0x45,
-	0x5f, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x02, 0x2a, 0x50, 0x0a, 0x0d, 0x43, 0x68, 0x61, 0x6c, 0x6c,
-	0x65, 0x6e, 0x67, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x56, 0x4f, 0x49, 0x44,
-	0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x49, 0x4e, 0x47, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x0a,
-	0x0a, 0x06, 0x44, 0x4f, 0x55, 0x42, 0x4c, 0x45, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x49,
-	0x56, 0x45, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x45,
-	0x4e, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x6f, 0x6d, 0x69, 0x6e, 0x6f, 0x31, 0x34,
-	0x2f, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2f, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x12, 0x27, 0x0a, 0x0f, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x70, 0x72, 0x61,
+	0x67, 0x6d, 0x61, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x75, 0x6e, 0x6b, 0x6e,
+	0x6f, 0x77, 0x6e, 0x50, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6f,
+	0x61, 0x72, 0x64, 0x5f, 0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x2f, 0x0a,
+	0x13, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6c, 0x65, 0x74, 0x74,
+	0x65, 0x72, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb3,
+	0x07, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x6f, 0x74, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x72, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x63, 0x6b,
+	0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x72, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x72, 0x6f, 0x77, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x39, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63, 0x77, 0x67, 0x61,
+	0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a,
+	0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x54, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x6f, 0x6e, 0x75, 0x73, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x62, 0x6f, 0x6e, 0x75, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x65, 0x6e,
+	0x64, 0x5f, 0x72, 0x61, 0x63, 0x6b, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0d, 0x65, 0x6e, 0x64, 0x52, 0x61, 0x63, 0x6b, 0x50, 0x6f, 0x69, 0x6e,
+	0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f, 0x73, 0x74, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x6f, 0x73, 0x74, 0x53, 0x63, 0x6f, 0x72,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x18, 0x10, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x12, 0x21, 0x0a, 0x0c,
+	0x77, 0x6f, 0x72, 0x64, 0x73, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x18, 0x11, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0b, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x46, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x12,
+	0x2e, 0x0a, 0x10, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e,
+	0x69, 0x6e, 0x67, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0f, 0x6d, 0x69, 0x6c,
+	0x6c, 0x69, 0x73, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12,
+	0x27, 0x0a, 0x0f, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x70, 0x72, 0x61, 0x67, 0x6d,
+	0x61, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77,
+	0x6e, 0x50, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x73, 0x12, 0x32, 0x0a, 0x0a, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63,
+	0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0a, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xd5, 0x01, 0x0a,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x54, 0x49, 0x4c, 0x45, 0x5f, 0x50, 0x4c,
+	0x41, 0x43, 0x45, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x00, 0x12, 0x18,
+	0x0a, 0x14, 0x50, 0x48, 0x4f, 0x4e, 0x59, 0x5f, 0x54, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x52, 0x45,
+	0x54, 0x55, 0x52, 0x4e, 0x45, 0x44, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x41, 0x53, 0x53,
+	0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x48, 0x41, 0x4c, 0x4c, 0x45, 0x4e, 0x47, 0x45, 0x5f,
+	0x42, 0x4f, 0x4e, 0x55, 0x53, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x58, 0x43, 0x48, 0x41,
+	0x4e, 0x47, 0x45, 0x10, 0x04, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x4e, 0x44, 0x5f, 0x52, 0x41, 0x43,
+	0x4b, 0x5f, 0x50, 0x54, 0x53, 0x10, 0x05, 0x12, 0x10, 0x0a, 0x0c, 0x54, 0x49, 0x4d, 0x45, 0x5f,
+	0x50, 0x45, 0x4e, 0x41, 0x4c, 0x54, 0x59, 0x10, 0x06, 0x12, 0x14, 0x0a, 0x10, 0x45, 0x4e, 0x44,
+	0x5f, 0x52, 0x41, 0x43, 0x4b, 0x5f, 0x50, 0x45, 0x4e, 0x41, 0x4c, 0x54, 0x59, 0x10, 0x07, 0x12,
+	0x24, 0x0a, 0x20, 0x55, 0x4e, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x46, 0x55, 0x4c, 0x5f,
+	0x43, 0x48, 0x41, 0x4c, 0x4c, 0x45, 0x4e, 0x47, 0x45, 0x5f, 0x54, 0x55, 0x52, 0x4e, 0x5f, 0x4c,
+	0x4f, 0x53, 0x53, 0x10, 0x08, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x48, 0x41, 0x4c, 0x4c, 0x45, 0x4e,
+	0x47, 0x45, 0x10, 0x09, 0x22, 0x29, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x0e, 0x0a, 0x0a, 0x48, 0x4f, 0x52, 0x49, 0x5a, 0x4f, 0x4e, 0x54, 0x41, 0x4c, 0x10,
+	0x00, 0x12, 0x0c, 0x0a, 0x08, 0x56, 0x45, 0x52, 0x54, 0x49, 0x43, 0x41, 0x4c, 0x10, 0x01, 0x42,
+	0x13, 0x0a, 0x11, 0x5f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x22, 0x8a, 0x01, 0x0a, 0x0a, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65,
+	0x2e, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x0a,
+	0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61,
+	0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x31,
+	0x0a, 0x0a, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x56, 0x61, 0x72, 0x69,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x76, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x9f, 0x01, 0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x50,
+	0x6c, 0x61, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x65, 0x61, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x61, 0x76,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x06, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79, 0x12, 0x1c, 0x0a, 0x07, 0x77, 0x69, 0x6e,
+	0x5f, 0x70, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x06, 0x77, 0x69,
+	0x6e, 0x50, 0x63, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x77, 0x69, 0x6e, 0x5f,
+	0x70, 0x63, 0x74, 0x22, 0x4a, 0x0a, 0x09, 0x56, 0x61, 0x72, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61,
+	0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22,
+	0x5e, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a,
+	0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x61,
+	0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65,
+	0x61, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22,
+	0x44, 0x0a, 0x0a, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x36, 0x0a,
+	0x0c, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d,
+	0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x0b, 0x67, 0x61, 0x6d, 0x65, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x5a, 0x0a, 0x0b, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x16, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0xd1, 0x01, 0x0a, 0x0e, 0x4e, 0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x50,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x65, 0x78, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x65, 0x78, 0x69, 0x63, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0e, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65,
+	0x6e, 0x67, 0x65, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15,
+	0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67,
+	0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65,
+	0x52, 0x75, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x67, 0x6f, 0x69, 0x6e, 0x67,
+	0x46, 0x69, 0x72, 0x73, 0x74, 0x22, 0x26, 0x0a, 0x0b, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x22, 0x80, 0x01,
+	0x0a, 0x11, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x05,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x77,
+	0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x5f,
+	0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67,
+	0x22, 0x85, 0x01, 0x0a, 0x12, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65,
+	0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e,
+	0x50, 0x6c, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x63, 0x6b, 0x2a, 0x43, 0x0a, 0x09, 0x50, 0x6c, 0x61, 0x79,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41, 0x59, 0x49, 0x4e, 0x47,
+	0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x4f,
+	0x52, 0x5f, 0x46, 0x49, 0x4e, 0x41, 0x4c, 0x5f, 0x50, 0x41, 0x53, 0x53, 0x10, 0x01, 0x12, 0x0d,
+	0x0a, 0x09, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x02, 0x2a, 0x50, 0x0a,
+	0x0d, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x08,
+	0x0a, 0x04, 0x56, 0x4f, 0x49, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x49, 0x4e, 0x47,
+	0x4c, 0x45, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x4f, 0x55, 0x42, 0x4c, 0x45, 0x10, 0x02,
+	0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x49, 0x56, 0x45, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x03,
+	0x12, 0x0d, 0x0a, 0x09, 0x54, 0x45, 0x4e, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x32,
+	0x39, 0x0a, 0x03, 0x42, 0x6f, 0x74, 0x12, 0x32, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x4d, 0x6f, 0x76,
+	0x65, 0x12, 0x12, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x42, 0x6f, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x42,
+	0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x80, 0x02, 0x0a, 0x0b, 0x47,
+	0x61, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x4e, 0x65,
+	0x77, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x4e,
+	0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x12, 0x3a, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x13, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61,
+	0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x63, 0x77, 0x67, 0x61,
+	0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x43,
+	0x0a, 0x0a, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x19, 0x2e, 0x63,
+	0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4d, 0x6f, 0x76, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65,
+	0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x13, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x47, 0x61, 0x6d,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x63, 0x77, 0x67, 0x61, 0x6d,
+	0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x2d, 0x5a,
+	0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x6f, 0x6d, 0x69,
+	0x6e, 0x6f, 0x31, 0x34, 0x2f, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x77, 0x67, 0x61, 0x6d, 0x65, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -947,32 +1560,58 @@ func file_proto_cwgame_cwgame_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_cwgame_cwgame_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_proto_cwgame_cwgame_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_cwgame_cwgame_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_proto_cwgame_cwgame_proto_goTypes = []interface{}{
-	(PlayState)(0),           // 0: cwgame.PlayState
-	(ChallengeRule)(0),       // 1: cwgame.ChallengeRule
-	(GameEvent_Type)(0),      // 2: cwgame.GameEvent.Type
-	(GameEvent_Direction)(0), // 3: cwgame.GameEvent.Direction
-	(*GameHistory)(nil),      // 4: cwgame.GameHistory
-	(*GameEvent)(nil),        // 5: cwgame.GameEvent
-	(*PlayerInfo)(nil),       // 6: cwgame.PlayerInfo
-	(*BotRequest)(nil),       // 7: cwgame.BotRequest
-	(*BotResponse)(nil),      // 8: cwgame.BotResponse
+	(PlayState)(0),             // 0: cwgame.PlayState
+	(ChallengeRule)(0),         // 1: cwgame.ChallengeRule
+	(GameEvent_Type)(0),        // 2: cwgame.GameEvent.Type
+	(GameEvent_Direction)(0),   // 3: cwgame.GameEvent.Direction
+	(*GameHistory)(nil),        // 4: cwgame.GameHistory
+	(*GameEvent)(nil),          // 5: cwgame.GameEvent
+	(*Annotation)(nil),         // 6: cwgame.Annotation
+	(*CandidatePlay)(nil),      // 7: cwgame.CandidatePlay
+	(*Variation)(nil),          // 8: cwgame.Variation
+	(*PlayerInfo)(nil),         // 9: cwgame.PlayerInfo
+	(*BotRequest)(nil),         // 10: cwgame.BotRequest
+	(*BotResponse)(nil),        // 11: cwgame.BotResponse
+	(*NewGameRequest)(nil),     // 12: cwgame.NewGameRequest
+	(*GameRequest)(nil),        // 13: cwgame.GameRequest
+	(*SubmitMoveRequest)(nil),  // 14: cwgame.SubmitMoveRequest
+	(*SubmitMoveResponse)(nil), // 15: cwgame.SubmitMoveResponse
 }
 var file_proto_cwgame_cwgame_proto_depIdxs = []int32{
-	5, // 0: cwgame.GameHistory.events:type_name -> cwgame.GameEvent
-	6, // 1: cwgame.GameHistory.players:type_name -> cwgame.PlayerInfo
-	1, // 2: cwgame.GameHistory.challenge_rule:type_name -> cwgame.ChallengeRule
-	0, // 3: cwgame.GameHistory.play_state:type_name -> cwgame.PlayState
-	2, // 4: cwgame.GameEvent.type:type_name -> cwgame.GameEvent.Type
-	3, // 5: cwgame.GameEvent.direction:type_name -> cwgame.GameEvent.Direction
-	4, // 6: cwgame.BotRequest.game_history:type_name -> cwgame.GameHistory
-	5, // 7: cwgame.BotResponse.move:type_name -> cwgame.GameEvent
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	5,  // 0: cwgame.GameHistory.events:type_name -> cwgame.GameEvent
+	9,  // 1: cwgame.GameHistory.players:type_name -> cwgame.PlayerInfo
+	1,  // 2: cwgame.GameHistory.challenge_rule:type_name -> cwgame.ChallengeRule
+	0,  // 3: cwgame.GameHistory.play_state:type_name -> cwgame.PlayState
+	2,  // 4: cwgame.GameEvent.type:type_name -> cwgame.GameEvent.Type
+	3,  // 5: cwgame.GameEvent.direction:type_name -> cwgame.GameEvent.Direction
+	6,  // 6: cwgame.GameEvent.annotation:type_name -> cwgame.Annotation
+	7,  // 7: cwgame.Annotation.candidates:type_name -> cwgame.CandidatePlay
+	8,  // 8: cwgame.Annotation.variations:type_name -> cwgame.Variation
+	5,  // 9: cwgame.Variation.events:type_name -> cwgame.GameEvent
+	4,  // 10: cwgame.BotRequest.game_history:type_name -> cwgame.GameHistory
+	5,  // 11: cwgame.BotResponse.move:type_name -> cwgame.GameEvent
+	9,  // 12: cwgame.NewGameRequest.players:type_name -> cwgame.PlayerInfo
+	1,  // 13: cwgame.NewGameRequest.challenge_rule:type_name -> cwgame.ChallengeRule
+	5,  // 14: cwgame.SubmitMoveRequest.event:type_name -> cwgame.GameEvent
+	5,  // 15: cwgame.SubmitMoveResponse.events:type_name -> cwgame.GameEvent
+	0,  // 16: cwgame.SubmitMoveResponse.play_state:type_name -> cwgame.PlayState
+	10, // 17: cwgame.Bot.GetMove:input_type -> cwgame.BotRequest
+	12, // 18: cwgame.GameService.NewGame:input_type -> cwgame.NewGameRequest
+	13, // 19: cwgame.GameService.GetGameHistory:input_type -> cwgame.GameRequest
+	14, // 20: cwgame.GameService.SubmitMove:input_type -> cwgame.SubmitMoveRequest
+	13, // 21: cwgame.GameService.StreamEvents:input_type -> cwgame.GameRequest
+	11, // 22: cwgame.Bot.GetMove:output_type -> cwgame.BotResponse
+	4,  // 23: cwgame.GameService.NewGame:output_type -> cwgame.GameHistory
+	4,  // 24: cwgame.GameService.GetGameHistory:output_type -> cwgame.GameHistory
+	15, // 25: cwgame.GameService.SubmitMove:output_type -> cwgame.SubmitMoveResponse
+	5,  // 26: cwgame.GameService.StreamEvents:output_type -> cwgame.GameEvent
+	22, // [22:27] is the sub-list for method output_type
+	17, // [17:22] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_proto_cwgame_cwgame_proto_init() }
@@ -1006,7 +1645,7 @@ func file_proto_cwgame_cwgame_proto_init() {
 			}
 		}
 		file_proto_cwgame_cwgame_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PlayerInfo); i {
+			switch v := v.(*Annotation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1018,7 +1657,7 @@ func file_proto_cwgame_cwgame_proto_init() {
 			}
 		}
 		file_proto_cwgame_cwgame_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BotRequest); i {
+			switch v := v.(*CandidatePlay); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1030,6 +1669,42 @@ func file_proto_cwgame_cwgame_proto_init() {
 			}
 		}
 		file_proto_cwgame_cwgame_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Variation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_cwgame_cwgame_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_cwgame_cwgame_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_cwgame_cwgame_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BotResponse); i {
 			case 0:
 				return &v.state
@@ -1041,8 +1716,58 @@ func file_proto_cwgame_cwgame_proto_init() {
 				return nil
 			}
 		}
+		file_proto_cwgame_cwgame_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_cwgame_cwgame_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_cwgame_cwgame_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitMoveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_cwgame_cwgame_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitMoveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_proto_cwgame_cwgame_proto_msgTypes[4].OneofWrappers = []interface{}{
+	file_proto_cwgame_cwgame_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_proto_cwgame_cwgame_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_proto_cwgame_cwgame_proto_msgTypes[7].OneofWrappers = []interface{}{
 		(*BotResponse_Move)(nil),
 		(*BotResponse_Error)(nil),
 	}
@@ -1052,9 +1777,9 @@ func file_proto_cwgame_cwgame_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_cwgame_cwgame_proto_rawDesc,
 			NumEnums:      4,
-			NumMessages:   5,
+			NumMessages:   12,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_cwgame_cwgame_proto_goTypes,
 		DependencyIndexes: file_proto_cwgame_cwgame_proto_depIdxs,
@@ -0,0 +1,320 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package cwgame
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// BotClient is the client API for Bot service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BotClient interface {
+	GetMove(ctx context.Context, in *BotRequest, opts ...grpc.CallOption) (*BotResponse, error)
+}
+
+type botClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBotClient(cc grpc.ClientConnInterface) BotClient {
+	return &botClient{cc}
+}
+
+func (c *botClient) GetMove(ctx context.Context, in *BotRequest, opts ...grpc.CallOption) (*BotResponse, error) {
+	out := new(BotResponse)
+	err := c.cc.Invoke(ctx, "/cwgame.Bot/GetMove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BotServer is the server API for Bot service.
+// All implementations must embed UnimplementedBotServer
+// for forward compatibility
+type BotServer interface {
+	GetMove(context.Context, *BotRequest) (*BotResponse, error)
+	mustEmbedUnimplementedBotServer()
+}
+
+// UnimplementedBotServer must be embedded to have forward compatible implementations.
+type UnimplementedBotServer struct {
+}
+
+func (UnimplementedBotServer) GetMove(context.Context, *BotRequest) (*BotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMove not implemented")
+}
+func (UnimplementedBotServer) mustEmbedUnimplementedBotServer() {}
+
+// UnsafeBotServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BotServer will
+// result in compilation errors.
+type UnsafeBotServer interface {
+	mustEmbedUnimplementedBotServer()
+}
+
+func RegisterBotServer(s grpc.ServiceRegistrar, srv BotServer) {
+	s.RegisterService(&_Bot_serviceDesc, srv)
+}
+
+func _Bot_GetMove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotServer).GetMove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cwgame.Bot/GetMove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotServer).GetMove(ctx, req.(*BotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Bot_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cwgame.Bot",
+	HandlerType: (*BotServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMove",
+			Handler:    _Bot_GetMove_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/cwgame/cwgame.proto",
+}
+
+// GameServiceClient is the client API for GameService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GameServiceClient interface {
+	NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*GameHistory, error)
+	GetGameHistory(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (*GameHistory, error)
+	SubmitMove(ctx context.Context, in *SubmitMoveRequest, opts ...grpc.CallOption) (*SubmitMoveResponse, error)
+	// StreamEvents sends every event in the game so far, followed by any
+	// new events as they happen. The stream ends when the game is over.
+	StreamEvents(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (GameService_StreamEventsClient, error)
+}
+
+type gameServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGameServiceClient(cc grpc.ClientConnInterface) GameServiceClient {
+	return &gameServiceClient{cc}
+}
+
+func (c *gameServiceClient) NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*GameHistory, error) {
+	out := new(GameHistory)
+	err := c.cc.Invoke(ctx, "/cwgame.GameService/NewGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) GetGameHistory(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (*GameHistory, error) {
+	out := new(GameHistory)
+	err := c.cc.Invoke(ctx, "/cwgame.GameService/GetGameHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) SubmitMove(ctx context.Context, in *SubmitMoveRequest, opts ...grpc.CallOption) (*SubmitMoveResponse, error) {
+	out := new(SubmitMoveResponse)
+	err := c.cc.Invoke(ctx, "/cwgame.GameService/SubmitMove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) StreamEvents(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (GameService_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GameService_serviceDesc.Streams[0], "/cwgame.GameService/StreamEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gameServiceStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GameService_StreamEventsClient interface {
+	Recv() (*GameEvent, error)
+	grpc.ClientStream
+}
+
+type gameServiceStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *gameServiceStreamEventsClient) Recv() (*GameEvent, error) {
+	m := new(GameEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GameServiceServer is the server API for GameService service.
+// All implementations must embed UnimplementedGameServiceServer
+// for forward compatibility
+type GameServiceServer interface {
+	NewGame(context.Context, *NewGameRequest) (*GameHistory, error)
+	GetGameHistory(context.Context, *GameRequest) (*GameHistory, error)
+	SubmitMove(context.Context, *SubmitMoveRequest) (*SubmitMoveResponse, error)
+	// StreamEvents sends every event in the game so far, followed by any
+	// new events as they happen. The stream ends when the game is over.
+	StreamEvents(*GameRequest, GameService_StreamEventsServer) error
+	mustEmbedUnimplementedGameServiceServer()
+}
+
+// UnimplementedGameServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedGameServiceServer struct {
+}
+
+func (UnimplementedGameServiceServer) NewGame(context.Context, *NewGameRequest) (*GameHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewGame not implemented")
+}
+func (UnimplementedGameServiceServer) GetGameHistory(context.Context, *GameRequest) (*GameHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGameHistory not implemented")
+}
+func (UnimplementedGameServiceServer) SubmitMove(context.Context, *SubmitMoveRequest) (*SubmitMoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitMove not implemented")
+}
+func (UnimplementedGameServiceServer) StreamEvents(*GameRequest, GameService_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedGameServiceServer) mustEmbedUnimplementedGameServiceServer() {}
+
+// UnsafeGameServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GameServiceServer will
+// result in compilation errors.
+type UnsafeGameServiceServer interface {
+	mustEmbedUnimplementedGameServiceServer()
+}
+
+func RegisterGameServiceServer(s grpc.ServiceRegistrar, srv GameServiceServer) {
+	s.RegisterService(&_GameService_serviceDesc, srv)
+}
+
+func _GameService_NewGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).NewGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cwgame.GameService/NewGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).NewGame(ctx, req.(*NewGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_GetGameHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).GetGameHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cwgame.GameService/GetGameHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).GetGameHistory(ctx, req.(*GameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_SubmitMove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitMoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).SubmitMove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cwgame.GameService/SubmitMove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).SubmitMove(ctx, req.(*SubmitMoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GameRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GameServiceServer).StreamEvents(m, &gameServiceStreamEventsServer{stream})
+}
+
+type GameService_StreamEventsServer interface {
+	Send(*GameEvent) error
+	grpc.ServerStream
+}
+
+type gameServiceStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *gameServiceStreamEventsServer) Send(m *GameEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _GameService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cwgame.GameService",
+	HandlerType: (*GameServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NewGame",
+			Handler:    _GameService_NewGame_Handler,
+		},
+		{
+			MethodName: "GetGameHistory",
+			Handler:    _GameService_GetGameHistory_Handler,
+		},
+		{
+			MethodName: "SubmitMove",
+			Handler:    _GameService_SubmitMove_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _GameService_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/cwgame/cwgame.proto",
+}
@@ -0,0 +1,164 @@
+// Package hint suggests plays for a rack, with the details a player
+// would want to see about each one.
+package hint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+// Filter limits the moves that are suggested. The zero Filter allows
+// every move.
+type Filter struct {
+	BingosOnly    bool
+	ExchangesOnly bool
+	// Through is a square, such as 8H, that every play must cover,
+	// either with a new tile or by playing through one on the board.
+	Through string
+}
+
+// A Word is a word formed by a play.
+type Word struct {
+	Word  string `json:"word"`
+	Valid bool   `json:"valid"`
+}
+
+// A Hint is a suggested move.
+type Hint struct {
+	Move             *move.Move `json:"-"`
+	ShortDescription string     `json:"shortDescription"`
+	Score            int        `json:"score"`
+	Leave            string     `json:"leave"`
+	Equity           float64    `json:"equity"`
+	// Words are the words the move forms, main word first. They are
+	// empty for exchanges and passes.
+	Words []Word `json:"words,omitempty"`
+}
+
+// Hinter suggests moves.
+type Hinter struct {
+	gen  movegen.MoveGenerator
+	calc equity.Calculator
+	lex  lexicon.Lexicon
+}
+
+// NewHinter creates a Hinter that finds moves with gen and ranks them
+// with calc. The words formed are checked against lex, which can be a
+// different word list from the one gen uses. If lex is nil, every word
+// is valid.
+func NewHinter(gen movegen.MoveGenerator, calc equity.Calculator, lex lexicon.Lexicon) *Hinter {
+	return &Hinter{gen: gen, calc: calc, lex: lex}
+}
+
+// Hints returns the top n moves for the player on turn in g, best equity
+// first. If n is 0 or less, every move that passes the filter is
+// returned.
+func (h *Hinter) Hints(g *game.Game, n int, f Filter) ([]*Hint, error) {
+	return h.HintsFor(g.Board(), g.Bag(), g.RackFor(g.PlayerOnTurn()), n, f)
+}
+
+// HintsFor returns the top n moves for any rack and position, best
+// equity first. If n is 0 or less, every move that passes the filter is
+// returned.
+func (h *Hinter) HintsFor(b *board.GameBoard, bag *alphabet.Bag, rack *alphabet.Rack,
+	n int, f Filter) ([]*Hint, error) {
+
+	through, err := parseSquare(f.Through, b)
+	if err != nil {
+		return nil, err
+	}
+	moves := h.gen.GenerateMoves(b, rack, bag.TilesRemaining())
+	equity.Rank(moves, h.calc, b, bag, nil)
+
+	hints := []*Hint{}
+	for _, m := range moves {
+		if n > 0 && len(hints) == n {
+			break
+		}
+		if !f.allows(m, through) {
+			continue
+		}
+		hint, err := h.hint(b, m)
+		if err != nil {
+			return nil, err
+		}
+		hints = append(hints, hint)
+	}
+	return hints, nil
+}
+
+func (h *Hinter) hint(b *board.GameBoard, m *move.Move) (*Hint, error) {
+	alph := m.Alphabet()
+	hint := &Hint{
+		Move:             m,
+		ShortDescription: m.ShortDescription(),
+		Score:            m.Score(),
+		Leave:            m.Leave().UserVisible(alph),
+		Equity:           m.Equity(),
+	}
+	if m.Action() != move.MoveTypePlay {
+		return hint, nil
+	}
+	words, err := b.FormedWords(m)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range words {
+		hint.Words = append(hint.Words, Word{
+			Word:  w.UserVisible(alph),
+			Valid: h.lex == nil || h.lex.HasWord(w),
+		})
+	}
+	return hint, nil
+}
+
+// square is a square on the board; row is -1 if there is none.
+type square struct {
+	row, col int
+}
+
+func parseSquare(s string, b *board.GameBoard) (square, error) {
+	if s == "" {
+		return square{-1, -1}, nil
+	}
+	s = strings.ToUpper(s)
+	row, col, vertical := move.FromBoardGameCoords(s)
+	if move.ToBoardGameCoords(row, col, vertical) != s || row >= b.Dim() || col >= b.Dim() {
+		return square{}, fmt.Errorf("%v is not a square on the board", s)
+	}
+	return square{row, col}, nil
+}
+
+func (f Filter) allows(m *move.Move, through square) bool {
+	if f.ExchangesOnly && m.Action() != move.MoveTypeExchange {
+		return false
+	}
+	if f.BingosOnly && (m.Action() != move.MoveTypePlay || m.TilesPlayed() < game.RackTileLimit) {
+		return false
+	}
+	if through.row < 0 {
+		return true
+	}
+	if m.Action() != move.MoveTypePlay {
+		return false
+	}
+	row, col, vertical := m.CoordsAndVertical()
+	for idx := range m.Tiles() {
+		r, c := row, col+idx
+		if vertical {
+			r, c = row+idx, col
+		}
+		if r == through.row && c == through.col {
+			return true
+		}
+	}
+	return false
+}
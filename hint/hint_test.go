@@ -0,0 +1,124 @@
+package hint
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+var words = []string{"DONATES", "JAVELINA", "ONSET", "STONED", "NOTED", "TONED", "DOTE",
+	"DOSE", "NOSE", "ONES", "TONES", "STONE", "NOTE", "TOE", "DOE", "ODE",
+	"SOD", "NOD", "TON", "NOT", "SET", "TEN", "NET", "TA", "AT", "AD", "DA",
+	"DE", "ED", "NO", "ON", "SO", "OS", "TO", "NE", "EN", "ES", "AS", "ID",
+	"IS", "IT", "YE", "YA", "YO", "WE", "NA", "AN", "AE", "OE", "DO", "OD"}
+
+// setup returns doug_v_emely with doug to play ADENOST.
+func setup(t *testing.T, lex ...string) (*game.Game, *Hinter) {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rules.LetterDistribution()
+	wl, err := lexicon.NewWordList("test", dist.Alphabet(), words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var checker lexicon.Lexicon
+	if len(lex) > 0 {
+		checker, err = lexicon.NewWordList("check", dist.Alphabet(), lex)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g, NewHinter(movegen.NewTrieGenerator(wl, dist), equity.NewLeaveCalculator(dist), checker)
+}
+
+func TestHints(t *testing.T) {
+	is := is.New(t)
+	g, h := setup(t)
+	hints, err := h.Hints(g, 5, Filter{})
+	is.NoErr(err)
+	is.Equal(len(hints), 5)
+	for i := 1; i < len(hints); i++ {
+		is.True(hints[i-1].Equity >= hints[i].Equity)
+	}
+	is.Equal(hints[0].Score, 82)
+	is.Equal(hints[0].ShortDescription, "10B DONATES")
+	is.Equal(hints[0].Leave, "")
+	is.Equal(hints[0].Words, []Word{{"DONATES", true}, {"JAVELINA", true}})
+
+	all, err := h.Hints(g, 0, Filter{})
+	is.NoErr(err)
+	is.True(len(all) > 5)
+	is.Equal(all[len(all)-1].Move.Action(), move.MoveTypeExchange)
+}
+
+func TestHintsCheckWords(t *testing.T) {
+	is := is.New(t)
+	g, h := setup(t, "DONATES")
+	hints, err := h.Hints(g, 1, Filter{})
+	is.NoErr(err)
+	is.Equal(hints[0].Words, []Word{{"DONATES", true}, {"JAVELINA", false}})
+}
+
+func TestHintsFilter(t *testing.T) {
+	is := is.New(t)
+	g, h := setup(t)
+
+	hints, err := h.Hints(g, 0, Filter{BingosOnly: true})
+	is.NoErr(err)
+	is.True(len(hints) > 0)
+	for _, hint := range hints {
+		is.Equal(hint.Move.TilesPlayed(), 7)
+	}
+
+	hints, err = h.Hints(g, 3, Filter{ExchangesOnly: true})
+	is.NoErr(err)
+	is.Equal(len(hints), 3)
+	for _, hint := range hints {
+		is.Equal(hint.Move.Action(), move.MoveTypeExchange)
+		is.Equal(len(hint.Words), 0)
+	}
+
+	// The Y of WINDY is on 8H.
+	hints, err = h.Hints(g, 0, Filter{Through: "h8"})
+	is.NoErr(err)
+	is.True(len(hints) > 0)
+	for _, hint := range hints {
+		is.True(Filter{Through: "8H"}.allows(hint.Move, square{7, 7}))
+		row, col, vertical := hint.Move.CoordsAndVertical()
+		if vertical {
+			is.Equal(col, 7)
+			is.True(row <= 7 && row+len(hint.Move.Tiles()) > 7)
+		} else {
+			is.Equal(row, 7)
+			is.True(col <= 7 && col+len(hint.Move.Tiles()) > 7)
+		}
+	}
+
+	for _, bad := range []string{"8", "16A", "Z1", "8H8"} {
+		_, err = h.Hints(g, 0, Filter{Through: bad})
+		is.True(err != nil)
+	}
+}
@@ -0,0 +1,190 @@
+// Package htmlio exports games as self-contained HTML pages, which show
+// the board, scores, racks and notes at every turn and can be stepped
+// through with the keyboard. The pages don't need any other files or a
+// network connection.
+package htmlio
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+var pageTemplate = template.Must(template.New("page").Parse(page))
+
+// position is the state of the game after some number of events.
+type position struct {
+	// Board has a string for every square; empty squares are "".
+	Board  [][]string `json:"board"`
+	Scores []int      `json:"scores"`
+	// Racks are the racks of the players, where known.
+	Racks  []string `json:"racks"`
+	OnTurn int      `json:"onTurn"`
+	InBag  int      `json:"inBag"`
+	// The event that led to this position, if any.
+	Nickname    string   `json:"nickname,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Note        string   `json:"note,omitempty"`
+	WordsFormed []string `json:"wordsFormed,omitempty"`
+	// Placed has the [row, column] of each tile placed by the event.
+	Placed [][2]int `json:"placed,omitempty"`
+	Over   bool     `json:"over"`
+	// The annotation of the event, if any.
+	Tags       []string     `json:"tags,omitempty"`
+	Candidates []*candidate `json:"candidates,omitempty"`
+	Variations []*variation `json:"variations,omitempty"`
+}
+
+type candidate struct {
+	Description string  `json:"description"`
+	Score       int32   `json:"score"`
+	Leave       string  `json:"leave"`
+	Equity      float64 `json:"equity"`
+	// WinPct is from 0 to 100, or nil if it is not known.
+	WinPct *float64 `json:"winPct"`
+}
+
+// variation is a line of play, with a summary of every event in it.
+type variation struct {
+	Name   string   `json:"name"`
+	Events []string `json:"events"`
+}
+
+type pageData struct {
+	Title   string
+	Players []string
+	// Bonuses has the CSS class of every square.
+	Bonuses   [][]string
+	Positions []*position
+}
+
+// GameHistoryToHTML writes an HTML page for the history. The game is
+// replayed with the given rules to find every position.
+func GameHistoryToHTML(w io.Writer, h *pb.GameHistory, rules *game.GameRules) error {
+	g, err := game.ReplayHistory(h, rules, 0)
+	if err != nil {
+		return err
+	}
+	data := &pageData{Title: h.Title}
+	for _, p := range h.Players {
+		data.Players = append(data.Players, p.Nickname)
+	}
+	if data.Title == "" && len(data.Players) == 2 {
+		data.Title = fmt.Sprintf("%v vs. %v", data.Players[0], data.Players[1])
+	}
+	dim := g.Board().Dim()
+	for r := 0; r < dim; r++ {
+		row := make([]string, dim)
+		for c := range row {
+			row[c] = bonusClasses[g.Board().GetBonus(r, c)]
+		}
+		data.Bonuses = append(data.Bonuses, row)
+	}
+
+	for t := 0; t <= len(h.Events); t++ {
+		err = g.PlayToTurn(t)
+		if err != nil {
+			return fmt.Errorf("cannot replay turn %d: %v", t+1, err)
+		}
+		pos := makePosition(g)
+		if t > 0 {
+			evt := h.Events[t-1]
+			pos.Nickname = evt.Nickname
+			pos.Summary = game.Summary(evt)
+			pos.Note = evt.Note
+			pos.WordsFormed = evt.WordsFormed
+			if evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE {
+				pos.Placed = placed(evt)
+			}
+			if evt.Annotation != nil {
+				annotate(pos, evt.Annotation)
+			}
+		}
+		if t < len(h.Events) {
+			// The rack of the player on turn is only known from their
+			// next move.
+			next := h.Events[t]
+			for i, nick := range data.Players {
+				if nick == next.Nickname {
+					pos.Racks[i] = next.Rack
+				}
+			}
+		}
+		data.Positions = append(data.Positions, pos)
+	}
+	return pageTemplate.Execute(w, data)
+}
+
+func makePosition(g *game.Game) *position {
+	pos := &position{
+		OnTurn: g.PlayerOnTurn(),
+		InBag:  g.Bag().TilesRemaining(),
+		Over:   g.Playing() == pb.PlayState_GAME_OVER,
+	}
+	for i := 0; i < g.NumPlayers(); i++ {
+		pos.Scores = append(pos.Scores, g.PointsFor(i))
+		pos.Racks = append(pos.Racks, "")
+	}
+	b := g.Board()
+	for r := 0; r < b.Dim(); r++ {
+		row := make([]string, b.Dim())
+		for c := range row {
+			if ml := b.GetLetter(r, c); ml != alphabet.EmptySquareMarker {
+				row[c] = string(ml.UserVisible(g.Alphabet()))
+			}
+		}
+		pos.Board = append(pos.Board, row)
+	}
+	return pos
+}
+
+func annotate(pos *position, a *pb.Annotation) {
+	pos.Tags = a.Tags
+	for _, c := range a.Candidates {
+		var winPct *float64
+		if c.WinPct != nil {
+			pct := *c.WinPct * 100
+			winPct = &pct
+		}
+		pos.Candidates = append(pos.Candidates, &candidate{
+			Description: c.Description, Score: c.Score, Leave: c.Leave,
+			Equity: c.Equity, WinPct: winPct})
+	}
+	for _, v := range a.Variations {
+		vr := &variation{Name: v.Name}
+		for _, evt := range v.Events {
+			vr.Events = append(vr.Events, game.Summary(evt))
+		}
+		pos.Variations = append(pos.Variations, vr)
+	}
+}
+
+// placed returns the squares that the tiles of a play were put on.
+func placed(evt *pb.GameEvent) [][2]int {
+	squares := [][2]int{}
+	r, c := int(evt.Row), int(evt.Column)
+	for _, t := range evt.PlayedTiles {
+		if t != alphabet.ASCIIPlayedThrough {
+			squares = append(squares, [2]int{r, c})
+		}
+		if evt.Direction == pb.GameEvent_VERTICAL {
+			r++
+		} else {
+			c++
+		}
+	}
+	return squares
+}
+
+// bonusClasses are the CSS classes of the bonus squares.
+var bonusClasses = map[board.BonusSquare]string{
+	board.Bonus3WS: "tws",
+	board.Bonus2WS: "dws",
+	board.Bonus3LS: "tls",
+	board.Bonus2LS: "dls",
+}
@@ -0,0 +1,133 @@
+package htmlio
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+var positionsRegex = regexp.MustCompile(`const positions = (.*);`)
+
+func TestGameHistoryToHTML(t *testing.T) {
+	is := is.New(t)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "english")
+	is.NoErr(err)
+
+	var out strings.Builder
+	is.NoErr(GameHistoryToHTML(&out, h, rules))
+	html := out.String()
+	is.True(strings.Contains(html, "<title>doug vs. emely</title>"))
+	// Nothing is loaded from anywhere else.
+	is.True(!strings.Contains(html, "src="))
+	is.True(!strings.Contains(html, "href="))
+
+	match := positionsRegex.FindStringSubmatch(html)
+	is.True(match != nil)
+	var positions []position
+	is.NoErr(json.Unmarshal([]byte(match[1]), &positions))
+	is.Equal(len(positions), len(h.Events)+1)
+
+	start := positions[0]
+	is.Equal(start.Scores, []int{0, 0})
+	is.Equal(start.Racks, []string{"DINNVWY", ""})
+	is.Equal(start.InBag, 86)
+
+	first := positions[1]
+	is.Equal(first.Board[7][3:8], []string{"W", "I", "N", "D", "Y"})
+	is.Equal(first.Placed, [][2]int{{7, 3}, {7, 4}, {7, 5}, {7, 6}, {7, 7}})
+	is.Equal(first.Scores, []int{32, 0})
+	is.Equal(first.Racks, []string{"", "ADEEGIL"})
+	is.Equal(first.Summary, "doug played 8D WINDY for 32 pts from a rack of DINNVWY")
+
+	// The phony TIL.. is taken back.
+	is.Equal(positions[6].Board[3][1], "T")
+	is.Equal(positions[7].Board[3][1], "")
+	is.Equal(positions[7].Scores[1], 55)
+
+	last := positions[len(positions)-1]
+	is.True(last.Over)
+	is.Equal(last.Scores, []int{451, 345})
+	// The history itself is left alone.
+	is.Equal(h.Uid, "")
+}
+
+func TestGameHistoryToHTMLVoidPhony(t *testing.T) {
+	is := is.New(t)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	is.Equal(h.ChallengeRule, pb.ChallengeRule_VOID)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "english")
+	is.NoErr(err)
+	// GALE is a phony that stayed on the board.
+	words := []string{}
+	for _, evt := range h.Events {
+		for _, w := range evt.WordsFormed {
+			if w != "GALE" {
+				words = append(words, w)
+			}
+		}
+	}
+	lex, err := lexicon.NewWordList("MOST", rules.LetterDistribution().Alphabet(), words)
+	is.NoErr(err)
+	rules = game.NewGameRules(&DefaultConfig, rules.LetterDistribution(), rules.Board(),
+		lex, rules.CrossSetGen())
+
+	var out strings.Builder
+	is.NoErr(GameHistoryToHTML(&out, h, rules))
+	is.Equal(h.ChallengeRule, pb.ChallengeRule_VOID)
+}
+
+func TestGameHistoryToHTMLAnnotation(t *testing.T) {
+	is := is.New(t)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "english")
+	is.NoErr(err)
+	h.Events[0].Annotation = &pb.Annotation{
+		Tags: []string{"best move"},
+		Candidates: []*pb.CandidatePlay{
+			{Description: "8D WINDY", Score: 32, Leave: "N", Equity: 35.5, WinPct: proto.Float64(0.5)},
+			{Description: "8H WINDY", Score: 30, Leave: "N", Equity: 33.5},
+			{Description: "8G WINDY", Score: 30, Leave: "N", Equity: 30, WinPct: proto.Float64(0)},
+		},
+		Variations: []*pb.Variation{{Name: "swap", Events: []*pb.GameEvent{{
+			Nickname: "doug", Rack: "DINNVWY", Type: pb.GameEvent_EXCHANGE, Exchanged: "VW"}}}},
+	}
+
+	var out strings.Builder
+	is.NoErr(GameHistoryToHTML(&out, h, rules))
+	match := positionsRegex.FindStringSubmatch(out.String())
+	is.True(match != nil)
+	var positions []position
+	is.NoErr(json.Unmarshal([]byte(match[1]), &positions))
+
+	first := positions[1]
+	is.Equal(first.Tags, []string{"best move"})
+	is.Equal(len(first.Candidates), 3)
+	c := first.Candidates[0]
+	is.Equal([]interface{}{c.Description, c.Score, c.Leave, c.Equity},
+		[]interface{}{"8D WINDY", int32(32), "N", 35.5})
+	is.Equal(*first.Candidates[0].WinPct, 50.0)
+	is.True(first.Candidates[1].WinPct == nil)
+	// A candidate that never wins is not the same as one with no win
+	// percentage.
+	is.True(first.Candidates[2].WinPct != nil)
+	is.Equal(*first.Candidates[2].WinPct, 0.0)
+	is.Equal(first.Variations[0].Events, []string{"doug exchanged VW from a rack of DINNVWY"})
+	is.Equal(len(positions[2].Candidates), 0)
+}
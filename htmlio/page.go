@@ -0,0 +1,177 @@
+package htmlio
+
+// page is the template of the HTML page. Everything it needs is inline.
+const page = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 1em; color: #222; }
+h1 { font-size: 1.3em; }
+#main { display: flex; flex-wrap: wrap; gap: 1.5em; }
+table.board { border-collapse: collapse; }
+table.board td {
+  width: 1.9em; height: 1.9em; border: 1px solid #999; text-align: center;
+  font-weight: bold; background: #f4f1e4;
+}
+td.tws { background: #e5534b; }
+td.dws { background: #f2a7a3; }
+td.tls { background: #3b82c4; }
+td.dls { background: #a8d0ef; }
+td.tile { background: #f7d774; }
+td.tile.blank { color: #b03030; }
+td.tile.new { background: #ffb84d; }
+td.label { border: none; background: none; font-weight: normal; color: #666; }
+#info { max-width: 30em; }
+.onturn { font-weight: bold; }
+#note { white-space: pre-wrap; background: #f6f6f6; padding: 0.5em; }
+#note:empty { display: none; }
+#annotation:empty { display: none; }
+#annotation table { border-collapse: collapse; }
+#annotation td, #annotation th { padding: 0.1em 0.6em; text-align: left; }
+.tag { background: #dde7f3; border-radius: 0.3em; padding: 0 0.3em; margin-right: 0.3em; }
+button { font-size: 1em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div id="main">
+<table class="board" id="board"></table>
+<div id="info">
+<p>
+<button id="first" title="Home">&laquo;</button>
+<button id="prev" title="Left arrow">&lsaquo;</button>
+<span id="turn"></span>
+<button id="next" title="Right arrow">&rsaquo;</button>
+<button id="last" title="End">&raquo;</button>
+</p>
+<table id="players"></table>
+<p id="bag"></p>
+<p id="summary"></p>
+<p id="words"></p>
+<div id="note"></div>
+<div id="annotation"></div>
+</div>
+</div>
+<script>
+"use strict";
+const players = {{.Players}};
+const bonuses = {{.Bonuses}};
+const positions = {{.Positions}};
+let current = positions.length - 1;
+
+function text(id, s) {
+  document.getElementById(id).textContent = s;
+}
+
+function show(n) {
+  current = Math.max(0, Math.min(positions.length - 1, n));
+  const pos = positions[current];
+  const placed = new Set((pos.placed || []).map(sq => sq[0] + "," + sq[1]));
+  const board = document.getElementById("board");
+  board.innerHTML = "";
+  const header = board.insertRow();
+  header.insertCell().className = "label";
+  for (let c = 0; c < bonuses.length; c++) {
+    const cell = header.insertCell();
+    cell.className = "label";
+    cell.textContent = String.fromCharCode(65 + c);
+  }
+  pos.board.forEach((row, r) => {
+    const tr = board.insertRow();
+    const label = tr.insertCell();
+    label.className = "label";
+    label.textContent = r + 1;
+    row.forEach((letter, c) => {
+      const cell = tr.insertCell();
+      cell.className = bonuses[r][c];
+      if (letter) {
+        cell.textContent = letter;
+        cell.className = "tile";
+        if (letter !== letter.toUpperCase()) {
+          cell.classList.add("blank");
+        }
+        if (placed.has(r + "," + c)) {
+          cell.classList.add("new");
+        }
+      }
+    });
+  });
+
+  const table = document.getElementById("players");
+  table.innerHTML = "";
+  players.forEach((nick, i) => {
+    const tr = table.insertRow();
+    if (!pos.over && pos.onTurn === i) {
+      tr.className = "onturn";
+    }
+    tr.insertCell().textContent = nick;
+    tr.insertCell().textContent = pos.scores[i];
+    tr.insertCell().textContent = pos.racks[i];
+  });
+  text("turn", "Turn " + current + " of " + (positions.length - 1));
+  text("bag", pos.inBag + " tiles in the bag" + (pos.over ? "; the game is over" : ""));
+  text("summary", pos.summary || "");
+  text("words", pos.wordsFormed ? "Words formed: " + pos.wordsFormed.join(", ") : "");
+  text("note", pos.note || "");
+  showAnnotation(pos);
+}
+
+function showAnnotation(pos) {
+  const div = document.getElementById("annotation");
+  div.innerHTML = "";
+  if (pos.tags) {
+    const p = document.createElement("p");
+    pos.tags.forEach(tag => {
+      const span = document.createElement("span");
+      span.className = "tag";
+      span.textContent = tag;
+      p.appendChild(span);
+    });
+    div.appendChild(p);
+  }
+  if (pos.candidates) {
+    const table = document.createElement("table");
+    const header = table.insertRow();
+    ["Move", "Score", "Leave", "Equity", "Win %"].forEach(h => {
+      const th = document.createElement("th");
+      th.textContent = h;
+      header.appendChild(th);
+    });
+    pos.candidates.forEach(c => {
+      const tr = table.insertRow();
+      tr.insertCell().textContent = c.description;
+      tr.insertCell().textContent = c.score;
+      tr.insertCell().textContent = c.leave;
+      tr.insertCell().textContent = c.equity.toFixed(1);
+      tr.insertCell().textContent = c.winPct === null ? "" : c.winPct.toFixed(1);
+    });
+    div.appendChild(table);
+  }
+  (pos.variations || []).forEach(v => {
+    const p = document.createElement("p");
+    p.textContent = (v.name ? v.name + ": " : "Variation: ") + (v.events || []).join("; ");
+    div.appendChild(p);
+  });
+}
+
+document.getElementById("first").onclick = () => show(0);
+document.getElementById("prev").onclick = () => show(current - 1);
+document.getElementById("next").onclick = () => show(current + 1);
+document.getElementById("last").onclick = () => show(positions.length - 1);
+document.addEventListener("keydown", e => {
+  switch (e.key) {
+  case "ArrowLeft": show(current - 1); break;
+  case "ArrowRight": show(current + 1); break;
+  case "Home": show(0); break;
+  case "End": show(positions.length - 1); break;
+  default: return;
+  }
+  e.preventDefault();
+});
+show(current);
+</script>
+</body>
+</html>
+`
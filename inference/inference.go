@@ -0,0 +1,292 @@
+// Package inference works out what an opponent is likely to be holding,
+// from what they chose to play.
+package inference
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+const (
+	// DefaultIterations is the number of racks tried for the opponent.
+	DefaultIterations = 500
+	// DefaultTau is how quickly a rack becomes unlikely as the opponent's
+	// play gets worse than the best one they had with it, in points of
+	// equity.
+	DefaultTau = 5.0
+)
+
+// Inferrer weights the leaves an opponent might have kept by trying out
+// racks they could have had before their last play or exchange. A rack
+// is likely if the move they made is about as good as the best move the
+// rack had; its weight falls off as exp(-d/Tau), where d is how much
+// worse the move was.
+type Inferrer struct {
+	gen  movegen.MoveGenerator
+	calc equity.Calculator
+
+	Iterations int
+	Tau        float64
+}
+
+// NewInferrer creates an Inferrer that judges moves with the given move
+// generator and equity calculator.
+func NewInferrer(gen movegen.MoveGenerator, calc equity.Calculator) *Inferrer {
+	return &Inferrer{gen: gen, calc: calc, Iterations: DefaultIterations, Tau: DefaultTau}
+}
+
+// Leave is a leave the opponent might have kept, with its weight.
+type Leave struct {
+	Tiles  alphabet.MachineWord
+	Weight float64
+}
+
+// Result is what was inferred about the opponent's rack.
+type Result struct {
+	// Event is the index of the opponent's play or exchange that the
+	// leaves were inferred from, or -1 if there was nothing to go on.
+	Event int
+	// Leaves are sorted from most to least likely, and their weights add
+	// up to 1. There are none if nothing could be inferred.
+	Leaves []Leave
+
+	unseen   alphabet.MachineWord
+	rackSize int
+}
+
+// Infer works out what the opponent of playerIdx is likely to hold after
+// the given number of events of a history, using only what playerIdx can
+// see.
+func (inf *Inferrer) Infer(h *pb.GameHistory, rules *game.GameRules, turn, playerIdx int,
+	rng *rand.Rand) (*Result, error) {
+
+	if turn < 0 || turn > len(h.Events) {
+		return nil, fmt.Errorf("turn %d is out of range", turn)
+	}
+	opp := 1 - playerIdx
+	g, err := game.ReplayHistory(h, rules, turn)
+	if err != nil {
+		return nil, err
+	}
+	unseen, err := game.UnseenAtTurn(h, rules, turn, playerIdx)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Event: -1, unseen: unseen.Tiles, rackSize: int(g.RackFor(opp).NumTiles())}
+
+	nick := h.Players[opp].Nickname
+	for e := turn - 1; e >= 0; e-- {
+		evt := h.Events[e]
+		if evt.Nickname != nick || (evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE &&
+			evt.Type != pb.GameEvent_EXCHANGE) {
+			continue
+		}
+		if e+1 < turn && h.Events[e+1].Type == pb.GameEvent_PHONY_TILES_RETURNED {
+			// The opponent took the play back, so it says little about
+			// what they kept.
+			return res, nil
+		}
+		res.Event = e
+		break
+	}
+	if res.Event == -1 {
+		return res, nil
+	}
+
+	weights, err := inf.weigh(h, rules, res.Event, playerIdx, rng)
+	if err != nil {
+		return nil, err
+	}
+	total := 0.0
+	for key, w := range weights {
+		leave := alphabet.MachineWord(key)
+		// Some leaves can be ruled out by tiles drawn since.
+		if _, ok := subtract(unseen.Tiles, leave); !ok || w == 0 {
+			continue
+		}
+		res.Leaves = append(res.Leaves, Leave{Tiles: leave, Weight: w})
+		total += w
+	}
+	for i := range res.Leaves {
+		res.Leaves[i].Weight /= total
+	}
+	sort.Slice(res.Leaves, func(i, j int) bool {
+		if res.Leaves[i].Weight != res.Leaves[j].Weight {
+			return res.Leaves[i].Weight > res.Leaves[j].Weight
+		}
+		return res.Leaves[i].Tiles.String() < res.Leaves[j].Tiles.String()
+	})
+	return res, nil
+}
+
+// weigh tries racks for the opponent's move at event e, returning the
+// total weight found for each leave, keyed by its sorted tiles.
+func (inf *Inferrer) weigh(h *pb.GameHistory, rules *game.GameRules, e, playerIdx int,
+	rng *rand.Rand) (map[string]float64, error) {
+
+	evt := h.Events[e]
+	g, err := game.ReplayHistory(h, rules, e)
+	if err != nil {
+		return nil, err
+	}
+	alph := g.Alphabet()
+	unseen, err := game.UnseenAtTurn(h, rules, e, playerIdx)
+	if err != nil {
+		return nil, err
+	}
+	size := int(g.RackFor(g.PlayerOnTurn()).NumTiles())
+	inBag := g.Bag().TilesRemaining()
+
+	var actual *move.Move
+	var played alphabet.MachineWord
+	pool := unseen.Tiles
+	if evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE {
+		actual = game.MoveFromEvent(evt, alph, g.Board())
+		if actual == nil {
+			return nil, fmt.Errorf("event %d is not a play on the board", e)
+		}
+		for _, ml := range actual.Tiles() {
+			if ml.IsPlayedTile() {
+				played = append(played, normalize(ml))
+			}
+		}
+		var ok bool
+		if pool, ok = subtract(unseen.Tiles, played); !ok {
+			return nil, fmt.Errorf("the play at event %d uses tiles that were already seen", e)
+		}
+	}
+	thrown := len([]rune(evt.Exchanged))
+
+	weights := map[string]float64{}
+	// Racks that have been tried already, and the leave and weight found.
+	tried := map[string]Leave{}
+	for i := 0; i < inf.Iterations; i++ {
+		tiles := append(append(alphabet.MachineWord{}, played...),
+			draw(pool, size-len(played), rng)...)
+		key := sorted(tiles).String()
+		l, ok := tried[key]
+		if !ok {
+			rack := alphabet.NewRack(alph)
+			rack.Set(tiles)
+			moves := inf.gen.GenerateMoves(g.Board(), rack, inBag)
+			equity.Rank(moves, inf.calc, g.Board(), g.Bag(), nil)
+			best := moves[0].Equity()
+
+			var chosen *move.Move
+			if actual != nil {
+				leave, _ := subtract(tiles, played)
+				row, col, vertical := actual.CoordsAndVertical()
+				chosen = move.NewScoringMove(actual.Score(), actual.Tiles(), leave,
+					vertical, actual.TilesPlayed(), alph, row, col, actual.BoardCoords())
+				chosen.SetEquity(inf.calc.Equity(chosen, g.Board(), g.Bag(), nil))
+			} else {
+				// The best exchange of as many tiles as were thrown back.
+				for _, m := range moves {
+					if m.Action() == move.MoveTypeExchange && len(m.Tiles()) == thrown {
+						chosen = m
+						break
+					}
+				}
+			}
+			if chosen != nil {
+				l = Leave{Tiles: sorted(chosen.Leave()),
+					Weight: math.Exp(-math.Max(0, best-chosen.Equity()) / inf.Tau)}
+			}
+			tried[key] = l
+		}
+		if l.Tiles != nil {
+			weights[l.Tiles.String()] += l.Weight
+		}
+	}
+	return weights, nil
+}
+
+// Sample returns a rack the opponent might have: a leave picked by its
+// weight, filled up with tiles drawn from the rest of the unseen tiles.
+// If nothing was inferred, the whole rack is drawn.
+func (r *Result) Sample(rng *rand.Rand) alphabet.MachineWord {
+	var leave alphabet.MachineWord
+	if len(r.Leaves) > 0 {
+		x := rng.Float64()
+		leave = r.Leaves[len(r.Leaves)-1].Tiles
+		for _, l := range r.Leaves {
+			if x < l.Weight {
+				leave = l.Tiles
+				break
+			}
+			x -= l.Weight
+		}
+	}
+	rest, _ := subtract(r.unseen, leave)
+	rack := append(append(alphabet.MachineWord{}, leave...), draw(rest, r.rackSize-len(leave), rng)...)
+	return sorted(rack)
+}
+
+// Racks returns n racks from Sample.
+func (r *Result) Racks(n int, rng *rand.Rand) []alphabet.MachineWord {
+	racks := make([]alphabet.MachineWord, n)
+	for i := range racks {
+		racks[i] = r.Sample(rng)
+	}
+	return racks
+}
+
+// normalize turns a blanked letter back into a blank.
+func normalize(ml alphabet.MachineLetter) alphabet.MachineLetter {
+	if ml.IsBlanked() {
+		return alphabet.BlankMachineLetter
+	}
+	return ml
+}
+
+func sorted(w alphabet.MachineWord) alphabet.MachineWord {
+	s := append(alphabet.MachineWord{}, w...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s
+}
+
+// subtract takes tiles out of a pool, returning false if the pool doesn't
+// have them all.
+func subtract(pool, tiles alphabet.MachineWord) (alphabet.MachineWord, bool) {
+	counts := map[alphabet.MachineLetter]int{}
+	for _, ml := range tiles {
+		counts[normalize(ml)]++
+	}
+	rest := alphabet.MachineWord{}
+	for _, ml := range pool {
+		if counts[ml] > 0 {
+			counts[ml]--
+			continue
+		}
+		rest = append(rest, ml)
+	}
+	for _, n := range counts {
+		if n > 0 {
+			return nil, false
+		}
+	}
+	return rest, true
+}
+
+// draw picks n tiles at random from a pool, or all of them if there
+// aren't that many.
+func draw(pool alphabet.MachineWord, n int, rng *rand.Rand) alphabet.MachineWord {
+	p := append(alphabet.MachineWord{}, pool...)
+	if n > len(p) {
+		n = len(p)
+	}
+	for i := 0; i < n; i++ {
+		j := i + rng.Intn(len(p)-i)
+		p[i], p[j] = p[j], p[i]
+	}
+	return p[:n]
+}
@@ -0,0 +1,135 @@
+package inference
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func setup(t *testing.T) (*pb.GameHistory, *game.GameRules, *Inferrer) {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rules.LetterDistribution()
+	wl, err := lexicon.NewWordList("test", dist.Alphabet(), []string{
+		"GALE", "WINDY", "AGE", "ALE", "LEG", "GEL", "GAL", "LAG", "DALE", "DEAL",
+		"LEAD", "GLADE", "GLIDE", "IDEA", "AIDE", "AIDED", "EGAD", "ID", "AD",
+		"DE", "ED", "EL", "AG", "AI", "IN", "WE", "YE", "GLAZE", "GAZE", "ZA",
+		"GALES", "GAVEL", "ALGAE", "EX", "AX", "OX", "YA", "YO", "NA", "NE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inf := NewInferrer(movegen.NewTrieGenerator(wl, dist), equity.NewLeaveCalculator(dist))
+	inf.Iterations = 200
+	return h, rules, inf
+}
+
+func TestInferAfterPlay(t *testing.T) {
+	is := is.New(t)
+	h, rules, inf := setup(t)
+	rng := rand.New(rand.NewSource(42))
+
+	// doug, after emely's GALE from ADEEGIL.
+	res, err := inf.Infer(h, rules, 2, 0, rng)
+	is.NoErr(err)
+	is.Equal(res.Event, 1)
+	is.True(len(res.Leaves) > 1)
+	total := 0.0
+	for i, l := range res.Leaves {
+		is.Equal(len(l.Tiles), 3)
+		if i > 0 {
+			is.True(l.Weight <= res.Leaves[i-1].Weight)
+		}
+		total += l.Weight
+	}
+	is.True(math.Abs(total-1) < 1e-9)
+
+	unseen, err := game.UnseenAtTurn(h, rules, 2, 0)
+	is.NoErr(err)
+	for _, rack := range res.Racks(50, rng) {
+		is.Equal(len(rack), 7)
+		_, ok := subtract(unseen.Tiles, rack)
+		is.True(ok)
+	}
+}
+
+func TestInferNothingToGoOn(t *testing.T) {
+	is := is.New(t)
+	h, rules, inf := setup(t)
+	rng := rand.New(rand.NewSource(42))
+
+	// emely hasn't moved yet.
+	res, err := inf.Infer(h, rules, 1, 0, rng)
+	is.NoErr(err)
+	is.Equal(res.Event, -1)
+	is.Equal(len(res.Leaves), 0)
+	is.Equal(len(res.Sample(rng)), 7)
+
+	// emely's TIL.. was taken back.
+	res, err = inf.Infer(h, rules, 7, 0, rng)
+	is.NoErr(err)
+	is.Equal(res.Event, -1)
+	is.Equal(len(res.Leaves), 0)
+
+	_, err = inf.Infer(h, rules, len(h.Events)+1, 0, rng)
+	is.True(err != nil)
+}
+
+func TestInferRulesOutBetterRacks(t *testing.T) {
+	is := is.New(t)
+	h, rules, inf := setup(t)
+	alph := rules.LetterDistribution().Alphabet()
+	// Going by score alone, with a tiny Tau, leaves that had a better play
+	// than GALE are all but ruled out.
+	inf.calc = equity.ScoreOnly{}
+	inf.Tau = 0.01
+	rng := rand.New(rand.NewSource(7))
+
+	res, err := inf.Infer(h, rules, 2, 0, rng)
+	is.NoErr(err)
+	is.True(len(res.Leaves) > 0)
+
+	g, err := game.ReplayHistory(h, rules, 1)
+	is.NoErr(err)
+	gale, err := alphabet.ToMachineWord("GALE", alph)
+	is.NoErr(err)
+	better, worse := 0, 0
+	for _, l := range res.Leaves {
+		rack := alphabet.NewRack(alph)
+		rack.Set(append(append(alphabet.MachineWord{}, gale...), l.Tiles...))
+		best := 0
+		for _, m := range inf.gen.GenerateMoves(g.Board(), rack, g.Bag().TilesRemaining()) {
+			if m.Score() > best {
+				best = m.Score()
+			}
+		}
+		if best > 16 {
+			better++
+			is.True(l.Weight < 1e-20)
+		} else {
+			worse++
+			is.True(l.Weight > 1e-6)
+		}
+	}
+	is.True(better > 0)
+	is.True(worse > 0)
+}
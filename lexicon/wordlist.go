@@ -0,0 +1,112 @@
+package lexicon
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/domino14/cwgame/alphabet"
+)
+
+// WordList is a Lexicon backed by a plain list of words. It is meant for
+// validating words; it does not support any sort of move generation.
+type WordList struct {
+	name  string
+	alph  *alphabet.Alphabet
+	words map[string]bool
+}
+
+// NewWordList creates a WordList from the given user-visible words. Words
+// are upper-cased; any word containing a letter that is not in the
+// alphabet causes an error.
+func NewWordList(name string, alph *alphabet.Alphabet, words []string) (*WordList, error) {
+	wl := &WordList{name: name, alph: alph, words: make(map[string]bool, len(words))}
+	for _, w := range words {
+		mw, err := alphabet.ToMachineWord(strings.ToUpper(w), alph)
+		if err != nil {
+			return nil, err
+		}
+		wl.words[mw.String()] = true
+	}
+	return wl, nil
+}
+
+// LoadWordList reads a word list with one word per line. Only the first
+// field of each line is used, so that files with definitions after the
+// word can be loaded as well. Empty lines and lines starting with # are
+// ignored.
+func LoadWordList(name string, alph *alphabet.Alphabet, r io.Reader) (*WordList, error) {
+	words := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		words = append(words, fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewWordList(name, alph, words)
+}
+
+// LoadWordListFile loads a word list from a file. The lexicon is named
+// after the file, without its extension (so NWL18.txt becomes NWL18).
+func LoadWordListFile(filename string, alph *alphabet.Alphabet) (*WordList, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return LoadWordList(name, alph, f)
+}
+
+func (wl *WordList) Name() string {
+	return wl.name
+}
+
+func (wl *WordList) GetAlphabet() *alphabet.Alphabet {
+	return wl.alph
+}
+
+// HasWord returns true if the word is in the list. Blanked letters are
+// treated as their natural letters.
+func (wl *WordList) HasWord(word Word) bool {
+	for _, ml := range word {
+		if ml.IsBlanked() {
+			unblanked := make(Word, len(word))
+			for i, l := range word {
+				unblanked[i] = l.Unblank()
+			}
+			return wl.words[unblanked.String()]
+		}
+	}
+	return wl.words[word.String()]
+}
+
+// Words returns all of the words in the list, sorted in machine-letter
+// order.
+func (wl *WordList) Words() []Word {
+	words := make([]Word, 0, len(wl.words))
+	for w := range wl.words {
+		mw := make(Word, len(w))
+		for i := 0; i < len(w); i++ {
+			mw[i] = alphabet.MachineLetter(w[i])
+		}
+		words = append(words, mw)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		return words[i].String() < words[j].String()
+	})
+	return words
+}
+
+// NumWords returns the number of words in the list.
+func (wl *WordList) NumWords() int {
+	return len(wl.words)
+}
@@ -0,0 +1,51 @@
+package lexicon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/alphabet"
+)
+
+func TestLoadWordList(t *testing.T) {
+	is := is.New(t)
+	alph := alphabet.EnglishAlphabet()
+	wl, err := LoadWordList("TEST", alph, strings.NewReader(`# a comment
+quixotic
+AA a kind of lava
+
+ZA pizza
+`))
+	is.NoErr(err)
+	is.Equal(wl.Name(), "TEST")
+	is.Equal(wl.NumWords(), 3)
+
+	for _, tc := range []struct {
+		word  string
+		valid bool
+	}{
+		{"QUIXOTIC", true},
+		{"AA", true},
+		{"ZA", true},
+		{"zA", true},
+		{"AZ", false},
+		{"LAVA", false},
+	} {
+		mw, err := alphabet.ToMachineWord(tc.word, alph)
+		is.NoErr(err)
+		is.Equal(wl.HasWord(mw), tc.valid)
+	}
+
+	words := wl.Words()
+	is.Equal(len(words), 3)
+	is.Equal(words[0].UserVisible(alph), "AA")
+	is.Equal(words[2].UserVisible(alph), "ZA")
+}
+
+func TestWordListBadLetter(t *testing.T) {
+	is := is.New(t)
+	_, err := NewWordList("TEST", alphabet.EnglishAlphabet(), []string{"CAFÉ"})
+	is.True(err != nil)
+}
@@ -0,0 +1,285 @@
+// Package montecarlo simulates candidate moves by playing them out a few
+// moves ahead, many times over, with racks the opponent might have.
+package montecarlo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+const (
+	// DefaultPlies is the number of moves played after each candidate.
+	DefaultPlies = 2
+	// minConfidenceIterations is the number of iterations needed before
+	// the confidence of the results is trusted.
+	minConfidenceIterations = 30
+)
+
+// RackSampler picks racks for the opponent. An *inference.Result is one.
+type RackSampler interface {
+	Sample(rng *rand.Rand) alphabet.MachineWord
+}
+
+// StopCondition says when a simulation is done. Any condition that is not
+// zero can stop it, whichever comes first.
+type StopCondition struct {
+	// Iterations is the number of iterations to run.
+	Iterations int
+	// Time is how long to run for.
+	Time time.Duration
+	// Confidence stops once the best candidate's mean spread is this many
+	// standard errors above every other candidate's; 2.58 is about 99%
+	// confidence.
+	Confidence float64
+}
+
+// Candidate is a move being simulated, and its results so far.
+type Candidate struct {
+	Move *move.Move
+	// Spread is the points the player gains on their opponent over the
+	// candidate and the plies after it.
+	Spread Stat
+	// Wins counts 1 when the player is ahead after the plies, 0.5 when
+	// tied and 0 when behind.
+	Wins Stat
+}
+
+// Simmer simulates moves. Each ply after the candidate is the best move
+// by equity for the player on turn.
+type Simmer struct {
+	gen  movegen.MoveGenerator
+	calc equity.Calculator
+
+	// Plies is the number of moves played out after each candidate.
+	Plies int
+	// Threads is the number of goroutines to simulate with.
+	Threads int
+	// Racks picks the opponent's rack for each iteration. If it is nil,
+	// the opponent gets a random rack from the tiles unseen by the player.
+	Racks RackSampler
+}
+
+// NewSimmer creates a Simmer that picks moves with the given move
+// generator and equity calculator.
+func NewSimmer(gen movegen.MoveGenerator, calc equity.Calculator) *Simmer {
+	return &Simmer{gen: gen, calc: calc, Plies: DefaultPlies, Threads: runtime.NumCPU()}
+}
+
+// sim is the shared state of a running simulation.
+type sim struct {
+	mu         sync.Mutex
+	candidates []*Candidate
+	started    int
+	done       bool
+	stop       StopCondition
+}
+
+// Simulate simulates the candidates for the player on turn in g, until
+// the stop condition is met or ctx is done. g is not changed. The
+// candidates are returned from best to worst by mean spread.
+func (s *Simmer) Simulate(ctx context.Context, g *game.Game, candidates []*move.Move,
+	stop StopCondition) ([]*Candidate, error) {
+
+	if stop.Iterations <= 0 && stop.Time <= 0 && stop.Confidence <= 0 {
+		return nil, errors.New("a simulation needs a stop condition")
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("there are no candidates to simulate")
+	}
+	if stop.Time > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, stop.Time)
+		defer cancel()
+	}
+	sm := &sim{stop: stop}
+	for _, m := range candidates {
+		sm.candidates = append(sm.candidates, &Candidate{Move: m})
+	}
+	threads := s.Threads
+	if threads < 1 {
+		threads = 1
+	}
+
+	// The other threads stop as soon as one of them fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make(chan error, threads)
+	for t := 0; t < threads; t++ {
+		wg.Add(1)
+		gc := g.Copy()
+		rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(t)))
+		go func() {
+			defer wg.Done()
+			if err := s.run(ctx, sm, gc, rng); err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	sorted := append([]*Candidate{}, sm.candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Spread.Mean() > sorted[j].Spread.Mean()
+	})
+	log.Debug().Int("iterations", sorted[0].Spread.Count()).Msg("simulation-done")
+	return sorted, nil
+}
+
+// claim starts an iteration, returning false if the simulation is over.
+func (sm *sim) claim(ctx context.Context) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.done || ctx.Err() != nil {
+		return false
+	}
+	if sm.stop.Iterations > 0 && sm.started >= sm.stop.Iterations {
+		return false
+	}
+	sm.started++
+	return true
+}
+
+// record adds the results of one iteration.
+func (sm *sim) record(spreads, wins []float64) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for i, c := range sm.candidates {
+		c.Spread.Push(spreads[i])
+		c.Wins.Push(wins[i])
+	}
+	if sm.stop.Confidence > 0 && sm.confident() {
+		sm.done = true
+	}
+}
+
+// confident returns true if the best candidate is clearly better than
+// all the others.
+func (sm *sim) confident() bool {
+	if len(sm.candidates) < 2 {
+		return true
+	}
+	best := sm.candidates[0]
+	for _, c := range sm.candidates[1:] {
+		if c.Spread.Mean() > best.Spread.Mean() {
+			best = c
+		}
+	}
+	if best.Spread.Count() < minConfidenceIterations {
+		return false
+	}
+	z := sm.stop.Confidence
+	for _, c := range sm.candidates {
+		if c == best {
+			continue
+		}
+		if best.Spread.Mean()-z*best.Spread.StdErr() <= c.Spread.Mean()+z*c.Spread.StdErr() {
+			return false
+		}
+	}
+	return true
+}
+
+// run does iterations on its own copy of the game until the simulation
+// is over.
+func (s *Simmer) run(ctx context.Context, sm *sim, g *game.Game, rng *rand.Rand) error {
+	player := g.PlayerOnTurn()
+	opp := 1 - player
+	g.SetBackupMode(game.SimulationMode)
+	g.SetStateStackLength(s.Plies + 2)
+
+	spreads := make([]float64, len(sm.candidates))
+	wins := make([]float64, len(sm.candidates))
+	for sm.claim(ctx) {
+		if err := s.dealOpponent(g, player, opp, rng); err != nil {
+			return err
+		}
+		for i, c := range sm.candidates {
+			before := g.SpreadFor(player)
+			if err := s.playOut(g, c.Move); err != nil {
+				return err
+			}
+			spreads[i] = float64(g.SpreadFor(player) - before)
+			switch after := g.SpreadFor(player); {
+			case after > 0:
+				wins[i] = 1
+			case after == 0:
+				wins[i] = 0.5
+			default:
+				wins[i] = 0
+			}
+			g.ResetToFirstState()
+		}
+		sm.record(spreads, wins)
+	}
+	return nil
+}
+
+// dealOpponent gives the opponent a new rack and shuffles the bag.
+func (s *Simmer) dealOpponent(g *game.Game, player, opp int, rng *rand.Rand) error {
+	if s.Racks == nil {
+		g.SetRandomRack(opp)
+	} else {
+		rack := alphabet.NewRack(g.Alphabet())
+		rack.Set(s.Racks.Sample(rng))
+		racks := make([]*alphabet.Rack, 2)
+		racks[player] = g.RackFor(player).Copy()
+		racks[opp] = rack
+		if err := g.SetRacksForBoth(racks); err != nil {
+			return err
+		}
+	}
+	g.Bag().Shuffle()
+	return nil
+}
+
+// Moves returns the moves the player on turn in g can make, ranked by
+// equity, best first.
+func (s *Simmer) Moves(g *game.Game) []*move.Move {
+	moves := s.gen.GenerateMoves(g.Board(), g.RackFor(g.PlayerOnTurn()),
+		g.Bag().TilesRemaining())
+	equity.Rank(moves, s.calc, g.Board(), g.Bag(), nil)
+	return moves
+}
+
+// playOut plays the candidate and the plies after it.
+func (s *Simmer) playOut(g *game.Game, m *move.Move) error {
+	if err := g.PlayMove(m, false, 0); err != nil {
+		return err
+	}
+	for ply := 0; ply < s.Plies; ply++ {
+		switch g.Playing() {
+		case pb.PlayState_GAME_OVER:
+			return nil
+		case pb.PlayState_WAITING_FOR_FINAL_PASS:
+			pass := move.NewPassMove(g.RackFor(g.PlayerOnTurn()).TilesOn(), g.Alphabet())
+			if err := g.PlayMove(pass, false, 0); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := g.PlayMove(s.Moves(g)[0], false, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package montecarlo
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+var words = []string{"WINDY", "GALE", "JAVELIN", "JAVE", "VOX", "DONATES", "EAU",
+	"ZA", "ZE", "JO", "JA", "OX", "AX", "EX", "XI", "NO", "ON", "AN", "NA", "NE",
+	"EN", "ES", "AS", "OS", "SO", "AE", "OE", "VAN", "VANE", "VANES", "JANES",
+	"SAVE", "OVEN", "OVENS", "NOSE", "ONES", "SANE", "SEA", "JOE", "JOES", "AVE",
+	"AVES", "NAVE", "NAVES", "EONS", "ODA", "ADO", "DA", "AD", "ID", "DE", "ED",
+	"WE", "YE", "YA", "YO", "IN", "AG", "LA", "AL", "EL", "GAL", "GALES"}
+
+func setup(t *testing.T) (*game.Game, *Simmer, []*move.Move) {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rules.LetterDistribution()
+	wl, err := lexicon.NewWordList("test", dist.Alphabet(), words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// doug to play, with AEJNOSV.
+	g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := movegen.NewTrieGenerator(wl, dist)
+	calc := equity.NewLeaveCalculator(dist)
+	moves := gen.GenerateMoves(g.Board(), g.RackFor(0), g.Bag().TilesRemaining())
+	equity.Rank(moves, calc, g.Board(), g.Bag(), nil)
+
+	// The best play and a pass.
+	candidates := []*move.Move{moves[0]}
+	for _, m := range moves {
+		if m.Action() == move.MoveTypePass {
+			candidates = append(candidates, m)
+		}
+	}
+	s := NewSimmer(gen, calc)
+	s.Threads = 2
+	return g, s, candidates
+}
+
+func TestSimulateIterations(t *testing.T) {
+	is := is.New(t)
+	g, s, candidates := setup(t)
+	before := g.ToDisplayText()
+
+	results, err := s.Simulate(context.Background(), g, candidates, StopCondition{Iterations: 40})
+	is.NoErr(err)
+	is.Equal(len(results), 2)
+	is.Equal(results[0].Move, candidates[0])
+	for _, c := range results {
+		is.Equal(c.Spread.Count(), 40)
+		is.Equal(c.Wins.Count(), 40)
+	}
+	is.True(results[0].Spread.Mean() > results[1].Spread.Mean())
+	// The simulation works on copies of the game.
+	is.Equal(g.ToDisplayText(), before)
+}
+
+func TestSimulateStops(t *testing.T) {
+	is := is.New(t)
+	g, s, candidates := setup(t)
+
+	_, err := s.Simulate(context.Background(), g, candidates, StopCondition{})
+	is.True(err != nil)
+
+	start := time.Now()
+	results, err := s.Simulate(context.Background(), g, candidates,
+		StopCondition{Time: 100 * time.Millisecond})
+	is.NoErr(err)
+	is.True(time.Since(start) < 5*time.Second)
+	is.True(results[0].Spread.Count() > 0)
+
+	results, err = s.Simulate(context.Background(), g, candidates,
+		StopCondition{Iterations: 100000, Confidence: 2.58})
+	is.NoErr(err)
+	n := results[0].Spread.Count()
+	is.True(n >= minConfidenceIterations)
+	is.True(n < 100000)
+}
+
+type fixedRacks struct {
+	mu    sync.Mutex
+	rack  alphabet.MachineWord
+	calls int
+	// bad is returned instead of rack on the first call.
+	bad alphabet.MachineWord
+}
+
+func (f *fixedRacks) Sample(rng *rand.Rand) alphabet.MachineWord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls == 1 && f.bad != nil {
+		return f.bad
+	}
+	return f.rack
+}
+
+func TestSimulateWithRacks(t *testing.T) {
+	is := is.New(t)
+	g, s, candidates := setup(t)
+	rack, err := alphabet.ToMachineWord("DEILOVX", g.Alphabet())
+	is.NoErr(err)
+	racks := &fixedRacks{rack: rack}
+	s.Racks = racks
+
+	results, err := s.Simulate(context.Background(), g, candidates, StopCondition{Iterations: 10})
+	is.NoErr(err)
+	is.Equal(racks.calls, 10)
+	is.Equal(results[0].Spread.Count(), 10)
+
+	// One thread failing stops the others.
+	racks = &fixedRacks{rack: rack}
+	racks.bad, err = alphabet.ToMachineWord("ZZZZZZZ", g.Alphabet())
+	is.NoErr(err)
+	s.Racks = racks
+	_, err = s.Simulate(context.Background(), g, candidates, StopCondition{Iterations: 100000})
+	is.True(err != nil)
+	is.True(racks.calls < 100)
+}
+
+func TestStat(t *testing.T) {
+	is := is.New(t)
+	var s Stat
+	for _, x := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.Push(x)
+	}
+	is.Equal(s.Count(), 8)
+	is.Equal(s.Mean(), 5.0)
+	is.True(s.Stdev() > 2.13 && s.Stdev() < 2.14)
+}
@@ -0,0 +1,44 @@
+package montecarlo
+
+import "math"
+
+// Stat keeps the running mean and variance of a series of values.
+type Stat struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+// Push adds a value.
+func (s *Stat) Push(x float64) {
+	s.n++
+	d := x - s.mean
+	s.mean += d / float64(s.n)
+	s.m2 += d * (x - s.mean)
+}
+
+// Count is the number of values pushed.
+func (s *Stat) Count() int {
+	return s.n
+}
+
+// Mean is the mean of the values.
+func (s *Stat) Mean() float64 {
+	return s.mean
+}
+
+// Stdev is the sample standard deviation of the values.
+func (s *Stat) Stdev() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	return math.Sqrt(s.m2 / float64(s.n-1))
+}
+
+// StdErr is the standard error of the mean.
+func (s *Stat) StdErr() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return s.Stdev() / math.Sqrt(float64(s.n))
+}
@@ -0,0 +1,244 @@
+package movegen
+
+import (
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/move"
+)
+
+// crossCheck is what the generator needs to know about an empty square
+// for plays in one direction.
+type crossCheck struct {
+	// allowed has a bit set for every letter that can go on the square.
+	allowed uint64
+	// word is true if a tile on the square makes a word across the play;
+	// score is then the score of the tiles already in that word.
+	word  bool
+	score int
+}
+
+// lineGen generates the plays along one row or column of the board.
+type lineGen struct {
+	gen      *TrieGenerator
+	b        *board.GameBoard
+	alph     *alphabet.Alphabet
+	vertical bool
+	line     int
+
+	rack     []int
+	numTiles int
+
+	checks []crossCheck
+	// connect[pos] is the first square at or after pos that a play must
+	// reach to be attached to the tiles on the board.
+	connect []int
+	start   int
+	word    alphabet.MachineWord
+	moves   *[]*move.Move
+}
+
+func (lg *lineGen) rowCol(pos int) (int, int) {
+	if lg.vertical {
+		return pos, lg.line
+	}
+	return lg.line, pos
+}
+
+func (lg *lineGen) letter(pos int) alphabet.MachineLetter {
+	return lg.b.GetLetter(lg.rowCol(pos))
+}
+
+func (lg *lineGen) occupied(pos int) bool {
+	return lg.letter(pos) != alphabet.EmptySquareMarker
+}
+
+func (lg *lineGen) isCenter(pos int) bool {
+	row, col := lg.rowCol(pos)
+	mid := lg.b.Dim() / 2
+	return lg.b.IsEmpty() && row == mid && col == mid
+}
+
+func (lg *lineGen) generate() {
+	dim := lg.b.Dim()
+	lg.checks = make([]crossCheck, dim)
+	lg.connect = make([]int, dim+1)
+	lg.connect[dim] = dim
+	for pos := dim - 1; pos >= 0; pos-- {
+		attached := lg.occupied(pos) || lg.isCenter(pos)
+		if !lg.occupied(pos) {
+			lg.checks[pos] = lg.crossCheck(pos)
+			attached = attached || lg.checks[pos].word
+		}
+		if attached {
+			lg.connect[pos] = pos
+		} else {
+			lg.connect[pos] = lg.connect[pos+1]
+		}
+	}
+	for lg.start = 0; lg.start < dim; lg.start++ {
+		if lg.start > 0 && lg.occupied(lg.start-1) {
+			// Plays start at the beginning of any word they go through.
+			continue
+		}
+		lg.word = lg.word[:0]
+		lg.extend(lg.start, lg.gen.trie.root, 0, false, false, 0, 1, 0)
+	}
+}
+
+// crossCheck works out which letters can go on an empty square, by
+// looking at the tiles on either side of it across the line.
+func (lg *lineGen) crossCheck(pos int) crossCheck {
+	all := uint64(1)<<lg.alph.NumLetters() - 1
+	row, col := lg.rowCol(pos)
+	dr, dc := 1, 0
+	if lg.vertical {
+		dr, dc = 0, 1
+	}
+	// Find where the tiles before the square start, and where the tiles
+	// after it end.
+	r0, c0 := row, col
+	for lg.b.PosExists(r0-dr, c0-dc) && lg.b.HasLetter(r0-dr, c0-dc) {
+		r0, c0 = r0-dr, c0-dc
+	}
+	r1, c1 := row, col
+	for lg.b.PosExists(r1+dr, c1+dc) && lg.b.HasLetter(r1+dr, c1+dc) {
+		r1, c1 = r1+dr, c1+dc
+	}
+	if r0 == r1 && c0 == c1 {
+		return crossCheck{allowed: all}
+	}
+	cc := crossCheck{word: true}
+	n := lg.gen.trie.root
+	for r, c := r0, c0; r != row || c != col; r, c = r+dr, c+dc {
+		ml := lg.b.GetLetter(r, c)
+		cc.score += lg.gen.dist.Score(ml)
+		if n != nil {
+			n = n.child(ml.Unblank())
+		}
+	}
+	for r, c := row+dr, col+dc; r <= r1 && c <= c1; r, c = r+dr, c+dc {
+		cc.score += lg.gen.dist.Score(lg.b.GetLetter(r, c))
+	}
+	if n == nil {
+		return cc
+	}
+	for i, l := range n.letters {
+		k := n.kids[i]
+		for r, c := row+dr, col+dc; k != nil && r <= r1 && c <= c1; r, c = r+dr, c+dc {
+			k = k.child(lg.b.GetLetter(r, c).Unblank())
+		}
+		if k != nil && k.word {
+			cc.allowed |= 1 << l
+		}
+	}
+	return cc
+}
+
+// extend spells out words from n onwards, starting at pos. The score of
+// the play so far is kept as the main word's letter score, its word
+// multiplier and the score of the cross words.
+func (lg *lineGen) extend(pos int, n *node, placed int, connected, firstCross bool,
+	main, mult, cross int) {
+
+	dim := lg.b.Dim()
+	if pos == dim || !lg.occupied(pos) {
+		// Vertical one-tile plays that make a word across are found as
+		// horizontal plays already.
+		dupe := lg.vertical && placed == 1 && firstCross
+		if n.word && placed > 0 && connected && pos-lg.start >= 2 && !dupe {
+			score := main*mult + cross
+			if placed == 7 {
+				score += 50
+			}
+			lg.record(placed, score)
+		}
+	}
+	if pos == dim {
+		return
+	}
+
+	if lg.occupied(pos) {
+		ml := lg.letter(pos)
+		c := n.child(ml.Unblank())
+		if c == nil {
+			return
+		}
+		lg.word = append(lg.word, alphabet.PlayedThroughMarker)
+		lg.extend(pos+1, c, placed, true, firstCross, main+lg.gen.dist.Score(ml), mult, cross)
+		lg.word = lg.word[:len(lg.word)-1]
+		return
+	}
+
+	if lg.numTiles == 0 {
+		return
+	}
+	if !connected {
+		// Give up if there aren't enough tiles left to reach the tiles on
+		// the board.
+		k := lg.connect[pos]
+		if k == dim {
+			return
+		}
+		need := k - pos
+		if !lg.occupied(k) {
+			need++
+		}
+		if need > lg.numTiles {
+			return
+		}
+	}
+
+	cc := lg.checks[pos]
+	letterMult, wordMult := 1, 1
+	row, col := lg.rowCol(pos)
+	switch lg.b.GetBonus(row, col) {
+	case board.Bonus3WS:
+		wordMult = 3
+	case board.Bonus2WS:
+		wordMult = 2
+	case board.Bonus3LS:
+		letterMult = 3
+	case board.Bonus2LS:
+		letterMult = 2
+	}
+	conn := connected || cc.word || lg.isCenter(pos)
+	if placed == 0 {
+		firstCross = cc.word
+	}
+
+	for i, l := range n.letters {
+		if cc.allowed&(1<<l) == 0 {
+			continue
+		}
+		for _, idx := range []alphabet.MachineLetter{l, alphabet.BlankMachineLetter} {
+			if lg.rack[idx] == 0 {
+				continue
+			}
+			ml := l
+			if idx == alphabet.BlankMachineLetter {
+				ml = l.Blank()
+			}
+			ls := lg.gen.dist.Score(ml) * letterMult
+			cs := 0
+			if cc.word {
+				cs = (ls + cc.score) * wordMult
+			}
+			lg.rack[idx]--
+			lg.numTiles--
+			lg.word = append(lg.word, ml)
+			lg.extend(pos+1, n.kids[i], placed+1, conn, firstCross,
+				main+ls, mult*wordMult, cross+cs)
+			lg.word = lg.word[:len(lg.word)-1]
+			lg.numTiles++
+			lg.rack[idx]++
+		}
+	}
+}
+
+func (lg *lineGen) record(placed, score int) {
+	tiles := append(alphabet.MachineWord{}, lg.word...)
+	row, col := lg.rowCol(lg.start)
+	coords := move.ToBoardGameCoords(row, col, lg.vertical)
+	*lg.moves = append(*lg.moves, move.NewScoringMove(score, tiles,
+		tilesOn(lg.rack, lg.alph), lg.vertical, placed, lg.alph, row, col, coords))
+}
@@ -0,0 +1,119 @@
+// Package movegen generates the moves that can be made with a rack.
+package movegen
+
+import (
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+)
+
+// MoveGenerator generates every move that can be made with a rack.
+type MoveGenerator interface {
+	// GenerateMoves returns all of the legal plays for the rack on the
+	// board, the exchanges allowed with tilesInBag tiles left in the bag,
+	// and a pass.
+	GenerateMoves(b *board.GameBoard, rack *alphabet.Rack, tilesInBag int) []*move.Move
+}
+
+// TrieGenerator is a MoveGenerator that finds plays by spelling out the
+// words of a Trie along every row and column of the board. It does not
+// use the cross-sets on the board; it works out what it needs as it goes,
+// so it can be used with any board.
+type TrieGenerator struct {
+	trie *Trie
+	dist *alphabet.LetterDistribution
+}
+
+// NewTrieGenerator creates a TrieGenerator for the words of a word list.
+func NewTrieGenerator(wl *lexicon.WordList, dist *alphabet.LetterDistribution) *TrieGenerator {
+	return &TrieGenerator{trie: NewTrie(wl.Words()), dist: dist}
+}
+
+// Trie returns the Trie that plays are generated from.
+func (gen *TrieGenerator) Trie() *Trie {
+	return gen.trie
+}
+
+// GenerateMoves implements MoveGenerator.
+func (gen *TrieGenerator) GenerateMoves(b *board.GameBoard, rack *alphabet.Rack,
+	tilesInBag int) []*move.Move {
+
+	alph := rack.Alphabet()
+	counts := make([]int, len(rack.LetArr))
+	copy(counts, rack.LetArr)
+
+	moves := []*move.Move{}
+	for _, vertical := range []bool{false, true} {
+		if vertical && b.IsEmpty() {
+			// Vertical opening plays are the same as horizontal ones.
+			break
+		}
+		for line := 0; line < b.Dim(); line++ {
+			lg := &lineGen{
+				gen: gen, b: b, alph: alph, vertical: vertical, line: line,
+				rack: counts, numTiles: int(rack.NumTiles()), moves: &moves,
+			}
+			lg.generate()
+		}
+	}
+	if tilesInBag >= game.ExchangeLimit {
+		moves = append(moves, exchanges(counts, alph)...)
+	}
+	moves = append(moves, move.NewPassMove(rack.TilesOn(), alph))
+	return moves
+}
+
+// tilesOn returns the tiles of a rack given as letter counts, with blanks
+// last.
+func tilesOn(counts []int, alph *alphabet.Alphabet) alphabet.MachineWord {
+	tiles := alphabet.MachineWord{}
+	for ml := 0; ml < int(alph.NumLetters()); ml++ {
+		for i := 0; i < counts[ml]; i++ {
+			tiles = append(tiles, alphabet.MachineLetter(ml))
+		}
+	}
+	for i := 0; i < counts[alphabet.BlankMachineLetter]; i++ {
+		tiles = append(tiles, alphabet.BlankMachineLetter)
+	}
+	return tiles
+}
+
+// exchanges returns every different exchange that can be made from a rack.
+func exchanges(counts []int, alph *alphabet.Alphabet) []*move.Move {
+	tiles := tilesOn(counts, alph)
+	moves := []*move.Move{}
+	kept := make([]int, len(counts))
+	copy(kept, counts)
+	thrown := alphabet.MachineWord{}
+
+	var visit func(i int)
+	visit = func(i int) {
+		if i == len(tiles) {
+			if len(thrown) > 0 {
+				moves = append(moves, move.NewExchangeMove(
+					append(alphabet.MachineWord{}, thrown...), tilesOn(kept, alph), alph))
+			}
+			return
+		}
+		// Decide how many of this letter to throw back, all at once, so
+		// that the same exchange is not made twice.
+		ml := tiles[i]
+		j := i
+		for j < len(tiles) && tiles[j] == ml {
+			j++
+		}
+		for n := 0; n <= j-i; n++ {
+			kept[ml] -= n
+			for k := 0; k < n; k++ {
+				thrown = append(thrown, ml)
+			}
+			visit(j)
+			thrown = thrown[:len(thrown)-n]
+			kept[ml] += n
+		}
+	}
+	visit(0)
+	return moves
+}
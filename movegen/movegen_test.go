@@ -0,0 +1,175 @@
+package movegen
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+var twos = []string{"AA", "AB", "AD", "AE", "AG", "AH", "AI", "AL", "AM", "AN",
+	"AR", "AS", "AT", "AW", "AX", "AY", "BA", "BE", "BI", "BO", "BY", "DE",
+	"DO", "ED", "EF", "EH", "EL", "EM", "EN", "ER", "ES", "EX", "FA", "FE",
+	"GO", "HA", "HE", "HI", "HM", "HO", "ID", "IF", "IN", "IS", "IT", "JO",
+	"KA", "KI", "LA", "LI", "LO", "MA", "ME", "MI", "MO", "MU", "MY", "NA",
+	"NE", "NO", "NU", "OD", "OE", "OF", "OH", "OI", "OM", "ON", "OP", "OR",
+	"OS", "OW", "OX", "OY", "PA", "PE", "PI", "QI", "RE", "SH", "SI", "SO",
+	"TA", "TI", "TO", "UH", "UM", "UN", "UP", "US", "UT", "WE", "WO", "XI",
+	"XU", "YA", "YE", "YO", "ZA"}
+
+// gameWords returns every word formed in a game, along with some two
+// letter words, so that the game can be played out with a small lexicon.
+func gameWords(t *testing.T, h *pb.GameHistory, rules *game.GameRules) []string {
+	words := append([]string{}, twos...)
+	alph := rules.LetterDistribution().Alphabet()
+	for i, evt := range h.Events {
+		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+			continue
+		}
+		g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := game.MoveFromEvent(evt, alph, g.Board())
+		formed, err := g.Board().FormedWords(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range formed {
+			words = append(words, w.UserVisible(alph))
+		}
+	}
+	return words
+}
+
+func sorted(w alphabet.MachineWord) string {
+	s := append(alphabet.MachineWord{}, w...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s.String()
+}
+
+func TestTrie(t *testing.T) {
+	is := is.New(t)
+	alph := alphabet.EnglishAlphabet()
+	words := []alphabet.MachineWord{}
+	for _, w := range []string{"CAT", "CATS", "CAT", "AT"} {
+		mw, err := alphabet.ToMachineWord(w, alph)
+		is.NoErr(err)
+		words = append(words, mw)
+	}
+	trie := NewTrie(words)
+	is.Equal(trie.NumWords(), 3)
+	cat, _ := alphabet.ToMachineWord("cAt", alph)
+	is.True(trie.HasWord(cat))
+	ca, _ := alphabet.ToMachineWord("CA", alph)
+	is.True(!trie.HasWord(ca))
+}
+
+func TestOpeningPlays(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	dist := rules.LetterDistribution()
+	alph := dist.Alphabet()
+	wl, err := lexicon.NewWordList("test", alph, []string{"CAT", "ACT", "AT", "TACO"})
+	is.NoErr(err)
+	gen := NewTrieGenerator(wl, dist)
+
+	b := rules.Board().Copy()
+	b.UpdateAllAnchors()
+	moves := gen.GenerateMoves(b, alphabet.RackFromString("ACTX", alph), 80)
+	plays := 0
+	for _, m := range moves {
+		if m.Action() != move.MoveTypePlay {
+			continue
+		}
+		plays++
+		_, _, vertical := m.CoordsAndVertical()
+		is.True(!vertical)
+		// Every opening play covers the double word center square.
+		if m.TilesPlayed() == 3 {
+			is.Equal(m.Score(), 10)
+		} else {
+			is.Equal(m.Score(), 4)
+		}
+	}
+	// CAT and ACT three ways each, AT two ways.
+	is.Equal(plays, 8)
+	// ACTX has 2^4 - 1 exchanges, and there is a pass.
+	is.Equal(len(moves), plays+15+1)
+	is.Equal(moves[len(moves)-1].Action(), move.MoveTypePass)
+
+	// No exchanges with an almost empty bag, and duplicate tiles are only
+	// thrown back one way.
+	moves = gen.GenerateMoves(b, alphabet.RackFromString("AAB", alph), 6)
+	is.Equal(len(moves), 1)
+	moves = gen.GenerateMoves(b, alphabet.RackFromString("AAB", alph), 7)
+	is.Equal(len(moves), 5+1)
+}
+
+// TestAgainstGame checks every play generated during a real game against
+// the game's own validation and scoring.
+func TestAgainstGame(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	dist := rules.LetterDistribution()
+	alph := dist.Alphabet()
+	wl, err := lexicon.NewWordList("test", alph, gameWords(t, h, rules))
+	is.NoErr(err)
+	gen := NewTrieGenerator(wl, dist)
+
+	for i, evt := range h.Events {
+		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+			continue
+		}
+		g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, i)
+		is.NoErr(err)
+		rack := g.RackFor(g.PlayerOnTurn())
+		moves := gen.GenerateMoves(g.Board(), rack, g.Bag().TilesRemaining())
+
+		seen := map[string]bool{}
+		for _, m := range moves {
+			if m.Action() != move.MoveTypePlay {
+				continue
+			}
+			desc := m.ShortDescription()
+			is.True(!seen[desc]) // each play is generated once
+			seen[desc] = true
+
+			row, col, vertical := m.CoordsAndVertical()
+			is.NoErr(g.Board().ErrorIfIllegalPlay(row, col, vertical, m.Tiles()))
+			formed, err := g.Board().FormedWords(m)
+			is.NoErr(err)
+			for _, w := range formed {
+				is.True(wl.HasWord(w))
+			}
+			scored, err := g.CreateAndScorePlacementMove(m.BoardCoords(),
+				m.Tiles().UserVisible(alph), rack.String())
+			is.NoErr(err)
+			if scored.Score() != m.Score() {
+				t.Fatalf("turn %d: %v scores %d, generated as %d", i, desc,
+					scored.Score(), m.Score())
+			}
+			is.Equal(sorted(scored.Leave()), sorted(m.Leave()))
+		}
+		actual := game.MoveFromEvent(evt, alph, g.Board())
+		if !seen[actual.ShortDescription()] {
+			t.Fatalf("turn %d: %v was not generated", i, actual.ShortDescription())
+		}
+	}
+}
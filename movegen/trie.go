@@ -0,0 +1,70 @@
+package movegen
+
+import (
+	"github.com/domino14/cwgame/alphabet"
+)
+
+// A Trie holds a list of words as a tree of letters, so that words can be
+// spelled out one letter at a time.
+type Trie struct {
+	root *node
+	size int
+}
+
+type node struct {
+	letters []alphabet.MachineLetter
+	kids    []*node
+	word    bool
+}
+
+// NewTrie builds a Trie of the given words. Blanked letters are treated as
+// their natural letters.
+func NewTrie(words []alphabet.MachineWord) *Trie {
+	t := &Trie{root: &node{}}
+	for _, w := range words {
+		n := t.root
+		for _, ml := range w {
+			n = n.add(ml.Unblank())
+		}
+		if !n.word {
+			n.word = true
+			t.size++
+		}
+	}
+	return t
+}
+
+func (n *node) child(ml alphabet.MachineLetter) *node {
+	for i, l := range n.letters {
+		if l == ml {
+			return n.kids[i]
+		}
+	}
+	return nil
+}
+
+func (n *node) add(ml alphabet.MachineLetter) *node {
+	if c := n.child(ml); c != nil {
+		return c
+	}
+	c := &node{}
+	n.letters = append(n.letters, ml)
+	n.kids = append(n.kids, c)
+	return c
+}
+
+// HasWord returns true if the word is in the Trie.
+func (t *Trie) HasWord(word alphabet.MachineWord) bool {
+	n := t.root
+	for _, ml := range word {
+		if n = n.child(ml.Unblank()); n == nil {
+			return false
+		}
+	}
+	return n.word
+}
+
+// NumWords returns the number of words in the Trie.
+func (t *Trie) NumWords() int {
+	return t.size
+}
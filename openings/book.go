@@ -0,0 +1,138 @@
+// Package openings builds and reads opening books: for each rack a game
+// can start with, the best known first moves, with their equity and how
+// they have fared in real games.
+package openings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/move"
+)
+
+// Opening is a first move.
+type Opening struct {
+	// Position is where a play goes, like "8D", and is empty for an
+	// exchange or a pass. Plays are always across on a board that is the
+	// same when transposed, as a play down is the same play there.
+	Position string `json:"position,omitempty"`
+	// Tiles are the tiles played or exchanged, and are empty for a pass.
+	Tiles  string  `json:"tiles,omitempty"`
+	Leave  string  `json:"leave"`
+	Score  int     `json:"score"`
+	Equity float64 `json:"equity"`
+	// Games counts the finished games of the corpus that started with
+	// this move, and Wins the ones its player went on to win. A tie
+	// counts as half a win.
+	Games int     `json:"games"`
+	Wins  float64 `json:"wins"`
+}
+
+// Description describes the move the way move.Move.ShortDescription does.
+func (o *Opening) Description() string {
+	switch {
+	case o.Position != "":
+		return fmt.Sprintf("%v %v", o.Position, o.Tiles)
+	case o.Tiles != "":
+		return fmt.Sprintf("(exch %v)", o.Tiles)
+	}
+	return "(Pass)"
+}
+
+// WinPct returns the fraction of its games the opening won, and false if
+// it has no games.
+func (o *Opening) WinPct() (float64, bool) {
+	if o.Games == 0 {
+		return 0, false
+	}
+	return o.Wins / float64(o.Games), true
+}
+
+// Book holds the openings of every rack, best equity first.
+type Book struct {
+	// Simulated is true if the equities are the mean spreads of
+	// simulations rather than static equities.
+	Simulated bool `json:"simulated"`
+	// Racks is keyed by the rack, as written by alphabet.Rack.String.
+	Racks map[string][]*Opening `json:"racks"`
+}
+
+// LoadBook reads a book written by Write.
+func LoadBook(r io.Reader) (*Book, error) {
+	bk := &Book{}
+	if err := json.NewDecoder(r).Decode(bk); err != nil {
+		return nil, err
+	}
+	if bk.Racks == nil {
+		return nil, errors.New("malformed opening book")
+	}
+	return bk, nil
+}
+
+// LoadBookFile reads a book from a file.
+func LoadBookFile(filename string) (*Book, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBook(f)
+}
+
+// Write writes the book as JSON.
+func (bk *Book) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(bk)
+}
+
+// Lookup returns the openings of rack, best first, or nil if the book
+// doesn't have it.
+func (bk *Book) Lookup(rack *alphabet.Rack) []*Opening {
+	return bk.Racks[rack.String()]
+}
+
+// Moves returns the openings of the player on turn in g as moves, best
+// first, with the equities of the book. It returns nil if the board isn't
+// empty or the book doesn't have the rack.
+func (bk *Book) Moves(g *game.Game) ([]*move.Move, error) {
+	if !g.Board().IsEmpty() {
+		return nil, nil
+	}
+	rack := g.RackFor(g.PlayerOnTurn())
+	moves := []*move.Move{}
+	for _, o := range bk.Lookup(rack) {
+		m, err := o.move(g, rack)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", o.Description(), err)
+		}
+		m.SetEquity(o.Equity)
+		moves = append(moves, m)
+	}
+	if len(moves) == 0 {
+		return nil, nil
+	}
+	return moves, nil
+}
+
+func (o *Opening) move(g *game.Game, rack *alphabet.Rack) (*move.Move, error) {
+	if o.Position != "" {
+		return g.CreateAndScorePlacementMove(o.Position, o.Tiles, rack.String())
+	}
+	alph := g.Alphabet()
+	leave, err := alphabet.ToMachineWord(o.Leave, alph)
+	if err != nil {
+		return nil, err
+	}
+	if o.Tiles == "" {
+		return move.NewPassMove(leave, alph), nil
+	}
+	tiles, err := alphabet.ToMachineWord(o.Tiles, alph)
+	if err != nil {
+		return nil, err
+	}
+	return move.NewExchangeMove(tiles, leave, alph), nil
+}
@@ -0,0 +1,282 @@
+package openings
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/montecarlo"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+// DefaultCandidates is how many generated moves a Builder adds to the
+// openings of each rack by default.
+const DefaultCandidates = 5
+
+// players are the players of the positions the builder ranks openings in.
+var players = []*pb.PlayerInfo{
+	{Nickname: "first", UserId: "first"},
+	{Nickname: "second", UserId: "second"},
+}
+
+// Builder builds a book from a corpus of games.
+type Builder struct {
+	rules *game.GameRules
+	gen   movegen.MoveGenerator
+	calc  equity.Calculator
+	// Candidates is how many of the moves with the best static equity
+	// are added to the openings seen in the corpus, for every rack.
+	Candidates int
+	// Sim, if not nil, ranks the openings of each rack by simulating
+	// them instead of by their static equity.
+	Sim *montecarlo.Simmer
+	// Stop says when each simulation stops.
+	Stop montecarlo.StopCondition
+
+	symmetric bool
+	racks     map[string]*rackOpenings
+}
+
+// rackOpenings are the openings found for a rack so far, keyed by their
+// description.
+type rackOpenings struct {
+	openings map[string]*Opening
+	moves    map[string]*move.Move
+}
+
+// NewBuilder creates a Builder for games with the given rules. Moves are
+// found with gen and ranked with calc; if gen is nil, only the openings
+// of the corpus go in the book.
+func NewBuilder(rules *game.GameRules, gen movegen.MoveGenerator, calc equity.Calculator) *Builder {
+	return &Builder{rules: rules, gen: gen, calc: calc, Candidates: DefaultCandidates,
+		Stop:      montecarlo.StopCondition{Iterations: 200},
+		symmetric: symmetric(rules.Board()),
+		racks:     map[string]*rackOpenings{}}
+}
+
+// symmetric returns true if b is the same when transposed.
+func symmetric(b *board.GameBoard) bool {
+	for i := 0; i < b.Dim(); i++ {
+		for j := i + 1; j < b.Dim(); j++ {
+			if b.GetBonus(i, j) != b.GetBonus(j, i) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// AddHistory adds the first move of a finished game, and whether its
+// player won. It returns false if the game isn't finished or doesn't
+// start with a known, full rack; a first play that was challenged off
+// doesn't count either. The history is not changed.
+func (b *Builder) AddHistory(h *pb.GameHistory) (bool, error) {
+	if len(h.Events) == 0 || len(h.Players) != 2 {
+		return false, nil
+	}
+	evt := h.Events[0]
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE, pb.GameEvent_EXCHANGE, pb.GameEvent_PASS:
+	default:
+		return false, nil
+	}
+	if len(h.Events) > 1 && h.Events[1].Type == pb.GameEvent_PHONY_TILES_RETURNED {
+		return false, nil
+	}
+
+	g, err := game.ReplayHistory(h, b.rules, 0)
+	if err != nil {
+		return false, err
+	}
+	rack := g.RackFor(g.PlayerOnTurn())
+	if evt.Rack == "" || int(rack.NumTiles()) != game.RackTileLimit {
+		return false, nil
+	}
+	// The rack changes as the rest of the game is played.
+	rackKey := rack.String()
+	m := game.MoveFromEvent(evt, g.Alphabet(), g.Board())
+	player := g.PlayerOnTurn()
+	if err := g.PlayToTurn(len(h.Events)); err != nil {
+		return false, err
+	}
+	if g.Playing() != pb.PlayState_GAME_OVER {
+		return false, nil
+	}
+
+	o := b.add(rackKey, m)
+	o.Games++
+	switch spread := g.SpreadFor(player); {
+	case spread > 0:
+		o.Wins++
+	case spread == 0:
+		o.Wins += 0.5
+	}
+	return true, nil
+}
+
+// AddDir adds the games of every GCG file in dir and its subdirectories,
+// and returns how many were added. Files that can't be read are logged
+// and skipped.
+func (b *Builder) AddDir(dir string) (int, error) {
+	added := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".gcg") {
+			return nil
+		}
+		h, err := gcgio.ParseGCG(b.rules.Config(), path)
+		if err != nil {
+			log.Warn().Err(err).Str("file", path).Msg("skipping game")
+			return nil
+		}
+		ok, err := b.AddHistory(h)
+		if err != nil {
+			log.Warn().Err(err).Str("file", path).Msg("skipping game")
+			return nil
+		}
+		if ok {
+			added++
+		}
+		return nil
+	})
+	return added, err
+}
+
+// add returns the opening for m, adding it if it is new.
+func (b *Builder) add(rack string, m *move.Move) *Opening {
+	ro, ok := b.racks[rack]
+	if !ok {
+		ro = &rackOpenings{openings: map[string]*Opening{}, moves: map[string]*move.Move{}}
+		b.racks[rack] = ro
+	}
+	o := b.opening(m)
+	key := o.Description()
+	if existing, ok := ro.openings[key]; ok {
+		return existing
+	}
+	ro.openings[key] = o
+	ro.moves[key] = m
+	return o
+}
+
+// opening describes m, with a play put across if the board is symmetric.
+// The leave is sorted, since moves made from a game event don't keep it in
+// any order.
+func (b *Builder) opening(m *move.Move) *Opening {
+	alph := b.rules.LetterDistribution().Alphabet()
+	leave := append(alphabet.MachineWord{}, m.Leave()...)
+	sort.Slice(leave, func(i, j int) bool { return leave[i] < leave[j] })
+	o := &Opening{Leave: leave.UserVisible(alph), Score: m.Score()}
+	switch m.Action() {
+	case move.MoveTypePlay:
+		row, col, vertical := m.CoordsAndVertical()
+		if vertical && b.symmetric {
+			row, col, vertical = col, row, false
+		}
+		o.Position = move.ToBoardGameCoords(row, col, vertical)
+		o.Tiles = m.Tiles().UserVisible(alph)
+	case move.MoveTypeExchange:
+		o.Tiles = m.Tiles().UserVisible(alph)
+	}
+	return o
+}
+
+// Build ranks the openings of every rack seen, and keeps the best n of
+// each in the book; all of them if n is 0 or less.
+func (b *Builder) Build(ctx context.Context, n int) (*Book, error) {
+	racks := []string{}
+	for rack := range b.racks {
+		racks = append(racks, rack)
+	}
+	sort.Strings(racks)
+
+	bk := &Book{Simulated: b.Sim != nil, Racks: map[string][]*Opening{}}
+	for _, rack := range racks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		openings, err := b.rank(ctx, rack)
+		if err != nil {
+			return nil, fmt.Errorf("rack %v: %v", rack, err)
+		}
+		if n > 0 && len(openings) > n {
+			openings = openings[:n]
+		}
+		bk.Racks[rack] = openings
+	}
+	return bk, nil
+}
+
+// rank returns the openings of rack, best first.
+func (b *Builder) rank(ctx context.Context, rack string) ([]*Opening, error) {
+	g, err := game.NewFromPosition(b.rules, players, &game.Position{Racks: [2]string{rack, ""}})
+	if err != nil {
+		return nil, err
+	}
+	if b.gen != nil && b.Candidates > 0 {
+		moves := b.gen.GenerateMoves(g.Board(), g.RackFor(0), g.Bag().TilesRemaining())
+		equity.Rank(moves, b.calc, g.Board(), g.Bag(), nil)
+		added := 0
+		for _, m := range moves {
+			if added == b.Candidates {
+				break
+			}
+			if _, ok := b.racks[rack].openings[b.opening(m).Description()]; ok {
+				continue
+			}
+			b.add(rack, m)
+			added++
+		}
+	}
+
+	ro := b.racks[rack]
+	keys := []string{}
+	for key := range ro.openings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if b.Sim == nil {
+		for _, key := range keys {
+			ro.openings[key].Equity = b.calc.Equity(ro.moves[key], g.Board(), g.Bag(), nil)
+		}
+	} else {
+		candidates := []*move.Move{}
+		for _, key := range keys {
+			candidates = append(candidates, ro.moves[key])
+		}
+		results, err := b.Sim.Simulate(ctx, g, candidates, b.Stop)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range results {
+			for _, key := range keys {
+				if ro.moves[key] == c.Move {
+					ro.openings[key].Equity = c.Spread.Mean()
+				}
+			}
+		}
+	}
+
+	openings := []*Opening{}
+	for _, key := range keys {
+		openings = append(openings, ro.openings[key])
+	}
+	sort.SliceStable(openings, func(i, j int) bool {
+		return openings[i].Equity > openings[j].Equity
+	})
+	return openings, nil
+}
@@ -0,0 +1,118 @@
+package openings
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// corpus returns a directory with two copies of doug_v_emely, which doug
+// opened with 8D WINDY from DINNVWY and won, and a file that isn't a game.
+func corpus(t *testing.T) string {
+	dir := t.TempDir()
+	gcg, err := os.ReadFile("../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "more"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string][]byte{
+		"a.gcg":         gcg,
+		"more/b.GCG":    gcg,
+		"more/notes.md": []byte("not a game"),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), contents, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBuildFromCorpus(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	calc := equity.NewLeaveCalculator(rules.LetterDistribution())
+	b := NewBuilder(rules, nil, calc)
+	added, err := b.AddDir(corpus(t))
+	is.NoErr(err)
+	is.Equal(added, 2)
+
+	bk, err := b.Build(context.Background(), 0)
+	is.NoErr(err)
+	is.Equal(len(bk.Racks), 1)
+	openings := bk.Racks["DINNVWY"]
+	is.Equal(len(openings), 1)
+	o := openings[0]
+	is.Equal(o.Description(), "8D WINDY")
+	is.Equal(o.Leave, "NV")
+	is.Equal(o.Score, 32)
+	is.Equal(o.Games, 2)
+	pct, ok := o.WinPct()
+	is.True(ok)
+	is.Equal(pct, 1.0)
+
+	var buf bytes.Buffer
+	is.NoErr(bk.Write(&buf))
+	loaded, err := LoadBook(&buf)
+	is.NoErr(err)
+	is.Equal(loaded, bk)
+}
+
+func TestBuildWithCandidates(t *testing.T) {
+	is := is.New(t)
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	is.NoErr(err)
+	ld := rules.LetterDistribution()
+	wl, err := lexicon.NewWordList("test", ld.Alphabet(), []string{"WINDY", "WIND", "WIN", "DIN", "INN"})
+	is.NoErr(err)
+	calc := equity.NewLeaveCalculator(ld)
+	b := NewBuilder(rules, movegen.NewTrieGenerator(wl, ld), calc)
+	b.Candidates = 10
+	_, err = b.AddDir(corpus(t))
+	is.NoErr(err)
+
+	bk, err := b.Build(context.Background(), 3)
+	is.NoErr(err)
+	openings := bk.Racks["DINNVWY"]
+	is.Equal(len(openings), 3)
+	seen := map[string]bool{}
+	for i, o := range openings {
+		// Plays down are put across, so no play is in the book twice.
+		is.True(o.Position[0] >= '1' && o.Position[0] <= '9')
+		is.True(!seen[o.Description()])
+		seen[o.Description()] = true
+		if i > 0 {
+			is.True(o.Equity <= openings[i-1].Equity)
+		}
+	}
+	_, ok := openings[len(openings)-1].WinPct()
+	is.True(!ok)
+
+	// The book's moves can be played in a new game.
+	g, err := game.NewFromPosition(rules, players, &game.Position{Racks: [2]string{"WINDYVN", ""}})
+	is.NoErr(err)
+	moves, err := bk.Moves(g)
+	is.NoErr(err)
+	is.Equal(len(moves), 3)
+	is.Equal(moves[0].ShortDescription(), openings[0].Description())
+	is.Equal(moves[0].Equity(), openings[0].Equity)
+	is.NoErr(g.PlayMove(moves[0], true, 0))
+	moves, err = bk.Moves(g)
+	is.NoErr(err)
+	is.True(moves == nil)
+}
@@ -0,0 +1,290 @@
+// Package preendgame analyses positions with a few tiles left in the bag,
+// by solving the endgames that every possible split of the unseen tiles
+// between the bag and the opponent's rack leads to.
+package preendgame
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/endgame"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+// MaxBagTiles is the most tiles the bag can have for a pre-endgame.
+const MaxBagTiles = 6
+
+// Result is how a candidate move does over every split of the unseen
+// tiles.
+type Result struct {
+	Move *move.Move
+	// Wins is the chance of winning, counting ties as half a win.
+	Wins float64
+	// Spread is the expected final spread of the player.
+	Spread float64
+	// EmptiesBag is true if the move draws every tile in the bag, so
+	// that the opponent is left in an endgame.
+	EmptiesBag bool
+	// Scenarios is the number of endgames that were solved.
+	Scenarios int
+	// Approximate is true if some scenario had a reply that leaves tiles
+	// in the bag, which is valued by the spread right after it instead of
+	// being searched to the end of the game.
+	Approximate bool
+}
+
+// Solver analyses pre-endgames. Every split of the unseen tiles is taken
+// to be known to both players, which makes each one a game of perfect
+// information.
+//
+// A move that empties the bag leads straight to an endgame. After one
+// that doesn't, such as a fishing play or a pass, the opponent picks
+// their best reply: replies that empty the bag are solved as endgames,
+// and the others are valued by the spread right after them. Only one
+// reply is looked at, so the results for such moves are approximate and
+// are marked as such.
+type Solver struct {
+	gen     movegen.MoveGenerator
+	endgame *endgame.Solver
+}
+
+// NewSolver creates a Solver that finds replies with gen and solves the
+// endgames with eg.
+func NewSolver(gen movegen.MoveGenerator, eg *endgame.Solver) *Solver {
+	return &Solver{gen: gen, endgame: eg}
+}
+
+// split is one way the unseen tiles can be divided, with its probability.
+type split struct {
+	opp  alphabet.MachineWord
+	bag  alphabet.MachineWord
+	prob float64
+}
+
+// Solve analyses the candidates for the player on turn in g. g is not
+// changed. The results are sorted by chance of winning, then by spread.
+// A result is only exact if every line it leads to ends in an endgame
+// or the end of the game within one reply; see Result.Approximate.
+func (s *Solver) Solve(ctx context.Context, g *game.Game, candidates []*move.Move) ([]*Result, error) {
+	inBag := g.Bag().TilesRemaining()
+	if inBag == 0 || inBag > MaxBagTiles {
+		return nil, fmt.Errorf("a pre-endgame needs 1 to %d tiles in the bag, not %d",
+			MaxBagTiles, inBag)
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("there are no candidates to analyse")
+	}
+	player := g.PlayerOnTurn()
+	opp := 1 - player
+	unseen, err := g.UnseenFor(player)
+	if err != nil {
+		return nil, err
+	}
+	oppSize := len(unseen.Tiles) - inBag
+	splits := []split{}
+	forEachSubset(unseen.Tiles, oppSize, func(oppRack, bag alphabet.MachineWord, prob float64) {
+		splits = append(splits, split{opp: oppRack, bag: bag, prob: prob})
+	})
+
+	results := []*Result{}
+	for _, m := range candidates {
+		res := &Result{Move: m, EmptiesBag: m.Action() == move.MoveTypePlay &&
+			m.TilesPlayed() >= inBag}
+		// The tiles the player keeps; only a play draws new ones, since
+		// there are too few in the bag to exchange.
+		kept, drawn := g.RackFor(player).TilesOn(), 0
+		if m.Action() == move.MoveTypePlay {
+			if kept, err = game.Leave(kept, m.Tiles()); err != nil {
+				return nil, err
+			}
+			drawn = m.TilesPlayed()
+		}
+		for _, sp := range splits {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			gc := g.Copy()
+			racks := make([]*alphabet.Rack, 2)
+			racks[player] = gc.RackFor(player).Copy()
+			racks[opp] = rackOf(sp.opp, gc.Alphabet())
+			if err := gc.SetRacksForBoth(racks); err != nil {
+				return nil, err
+			}
+			if err := gc.PlayMove(m, false, 0); err != nil {
+				return nil, err
+			}
+			err := s.afterDraw(ctx, gc, player, kept, sp.opp, sp.bag, drawn,
+				func(spread int, approximate bool, prob float64) {
+					res.add(spread, sp.prob*prob)
+					res.Approximate = res.Approximate || approximate
+				})
+			if err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, res)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Wins != results[j].Wins {
+			return results[i].Wins > results[j].Wins
+		}
+		return results[i].Spread > results[j].Spread
+	})
+	return results, nil
+}
+
+func (r *Result) add(spread int, prob float64) {
+	r.Scenarios++
+	r.Spread += float64(spread) * prob
+	switch {
+	case spread > 0:
+		r.Wins += prob
+	case spread == 0:
+		r.Wins += prob / 2
+	}
+}
+
+// afterDraw goes through every set of tiles the player can draw after
+// their move, calling fn with the final spread each one leads to and
+// whether it is approximate.
+func (s *Solver) afterDraw(ctx context.Context, g *game.Game, player int,
+	leave, oppRack, bag alphabet.MachineWord, drawn int,
+	fn func(spread int, approximate bool, prob float64)) error {
+
+	if drawn > len(bag) {
+		drawn = len(bag)
+	}
+	var err error
+	forEachSubset(bag, drawn, func(draw, rest alphabet.MachineWord, prob float64) {
+		if err != nil {
+			return
+		}
+		gc := g.Copy()
+		racks := make([]*alphabet.Rack, 2)
+		racks[player] = rackOf(append(append(alphabet.MachineWord{}, leave...), draw...), gc.Alphabet())
+		racks[1-player] = rackOf(oppRack, gc.Alphabet())
+		if err = gc.SetRacksForBoth(racks); err != nil {
+			return
+		}
+		var spread int
+		var approximate bool
+		if spread, approximate, err = s.spreadAfter(ctx, gc, player); err == nil {
+			fn(spread, approximate, prob)
+		}
+	})
+	return err
+}
+
+// spreadAfter is the final spread for player, with their opponent to
+// move. It is approximate if a reply that leaves tiles in the bag had to
+// be valued by the spread right after it, since the game is not followed
+// any further than one reply.
+func (s *Solver) spreadAfter(ctx context.Context, g *game.Game, player int) (int, bool, error) {
+	if g.Playing() == pb.PlayState_GAME_OVER {
+		return g.SpreadFor(player), false, nil
+	}
+	if g.Bag().TilesRemaining() == 0 {
+		sol, err := s.endgame.Solve(ctx, g)
+		if err != nil {
+			return 0, false, err
+		}
+		return -sol.Spread, false, nil
+	}
+	// The opponent picks the reply that is worst for the player.
+	inBag := g.Bag().TilesRemaining()
+	worst, approximate := 0, false
+	replies := s.gen.GenerateMoves(g.Board(), g.RackFor(1-player), inBag)
+	for i, m := range replies {
+		gc := g.Copy()
+		if err := gc.PlayMove(m, false, 0); err != nil {
+			return 0, false, err
+		}
+		spread := gc.SpreadFor(player)
+		switch {
+		case gc.Playing() == pb.PlayState_GAME_OVER:
+		case m.Action() == move.MoveTypePlay && m.TilesPlayed() >= inBag:
+			sol, err := s.endgame.Solve(ctx, gc)
+			if err != nil {
+				return 0, false, err
+			}
+			spread = sol.Spread
+		default:
+			approximate = true
+		}
+		if i == 0 || spread < worst {
+			worst = spread
+		}
+	}
+	return worst, approximate, nil
+}
+
+func rackOf(tiles alphabet.MachineWord, alph *alphabet.Alphabet) *alphabet.Rack {
+	rack := alphabet.NewRack(alph)
+	rack.Set(tiles)
+	return rack
+}
+
+// forEachSubset calls fn with every different set of n tiles that can be
+// picked from tiles, the tiles left over, and the chance of picking them.
+func forEachSubset(tiles alphabet.MachineWord, n int,
+	fn func(picked, rest alphabet.MachineWord, prob float64)) {
+
+	counts := map[alphabet.MachineLetter]int{}
+	letters := alphabet.MachineWord{}
+	for _, ml := range tiles {
+		if counts[ml] == 0 {
+			letters = append(letters, ml)
+		}
+		counts[ml]++
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	all := choose(len(tiles), n)
+
+	picked := alphabet.MachineWord{}
+	var visit func(i, left int, ways float64)
+	visit = func(i, left int, ways float64) {
+		if i == len(letters) {
+			if left > 0 {
+				return
+			}
+			rest := alphabet.MachineWord{}
+			taken := map[alphabet.MachineLetter]int{}
+			for _, ml := range picked {
+				taken[ml]++
+			}
+			for _, ml := range letters {
+				for k := taken[ml]; k < counts[ml]; k++ {
+					rest = append(rest, ml)
+				}
+			}
+			fn(append(alphabet.MachineWord{}, picked...), rest, ways/all)
+			return
+		}
+		ml := letters[i]
+		for k := 0; k <= counts[ml] && k <= left; k++ {
+			for j := 0; j < k; j++ {
+				picked = append(picked, ml)
+			}
+			visit(i+1, left-k, ways*choose(counts[ml], k))
+			picked = picked[:len(picked)-k]
+		}
+	}
+	visit(0, n, 1)
+}
+
+func choose(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	c := 1.0
+	for i := 1; i <= k; i++ {
+		c = c * float64(n-k+i) / float64(i)
+	}
+	return c
+}
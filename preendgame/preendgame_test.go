@@ -0,0 +1,139 @@
+package preendgame
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/endgame"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// preendgame sets up doug_v_emely with emely to play ?HOQSTU and one tile
+// in the bag. It returns the game, emely's QUOTH and a pass.
+func preendgame(t *testing.T, turn int) (*game.Game, *movegen.TrieGenerator, []*move.Move) {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, turn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rules.LetterDistribution()
+	words := []string{"QUOTH", "HIM", "FAS", "FEH", "PEG", "POGO", "EGO", "OF",
+		"HM", "HO", "HI", "MI", "MO", "OM", "OP", "PE", "PI", "PO", "OPE", "GEO",
+		"GOPHER", "HOME", "MOPE", "HOPE", "HE", "ME", "EH", "EM", "GO", "IF"}
+	wl, err := lexicon.NewWordList("test", dist.Alphabet(), words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quoth := game.MoveFromEvent(h.Events[24], dist.Alphabet(), g.Board())
+	pass := move.NewPassMove(g.RackFor(g.PlayerOnTurn()).TilesOn(), dist.Alphabet())
+	return g, movegen.NewTrieGenerator(wl, dist), []*move.Move{pass, quoth}
+}
+
+func TestSolve(t *testing.T) {
+	is := is.New(t)
+	g, gen, candidates := preendgame(t, 24)
+	is.Equal(g.Bag().TilesRemaining(), 1)
+	eg := endgame.NewSolver(gen)
+	eg.MaxPlies = 2
+
+	results, err := NewSolver(gen, eg).Solve(context.Background(), g, candidates)
+	is.NoErr(err)
+	is.Equal(len(results), 2)
+	is.Equal(g.Bag().TilesRemaining(), 1)
+
+	var quoth, pass *Result
+	for _, r := range results {
+		is.True(r.Wins >= 0 && r.Wins <= 1)
+		if r.Move == candidates[1] {
+			quoth = r
+		} else {
+			pass = r
+		}
+	}
+	// Any one of the eight tiles doug could have, EFGHIMOP, can be in the bag.
+	is.True(quoth.EmptiesBag)
+	is.Equal(quoth.Scenarios, 8)
+	is.True(!pass.EmptiesBag)
+	is.Equal(pass.Scenarios, 8)
+	// doug can pass back, which leaves the tile in the bag.
+	is.True(!quoth.Approximate)
+	is.True(pass.Approximate)
+	is.True(quoth.Spread > pass.Spread)
+
+	// Each split is as likely as any other, so QUOTH's spread is the
+	// average of the eight endgames.
+	sum := 0.0
+	emely := g.PlayerOnTurn()
+	for _, tile := range "EFGHIMOP" {
+		gc := g.Copy()
+		unseen, err := gc.UnseenFor(emely)
+		is.NoErr(err)
+		doug := alphabet.MachineWord{}
+		for _, ml := range unseen.Tiles {
+			if ml.UserVisible(gc.Alphabet()) != tile {
+				doug = append(doug, ml)
+			}
+		}
+		is.Equal(len(doug), 7)
+		racks := make([]*alphabet.Rack, 2)
+		racks[emely] = gc.RackFor(emely).Copy()
+		racks[1-emely] = rackOf(doug, gc.Alphabet())
+		is.NoErr(gc.SetRacksForBoth(racks))
+		is.NoErr(gc.PlayMove(candidates[1], false, 0))
+		is.Equal(gc.Bag().TilesRemaining(), 0)
+		sol, err := eg.Solve(context.Background(), gc)
+		is.NoErr(err)
+		sum -= float64(sol.Spread)
+	}
+	is.True(math.Abs(quoth.Spread-sum/8) < 1e-9)
+}
+
+func TestSolveNeedsTilesInBag(t *testing.T) {
+	is := is.New(t)
+	g, gen, candidates := preendgame(t, 26)
+	s := NewSolver(gen, endgame.NewSolver(gen))
+	_, err := s.Solve(context.Background(), g, candidates[:1])
+	is.True(err != nil)
+
+	g, _, _ = preendgame(t, 24)
+	_, err = s.Solve(context.Background(), g, nil)
+	is.True(err != nil)
+}
+
+func TestForEachSubset(t *testing.T) {
+	is := is.New(t)
+	tiles := alphabet.MachineWord{0, 0, 0, 1, 1, 2}
+	total, n := 0.0, 0
+	forEachSubset(tiles, 3, func(picked, rest alphabet.MachineWord, prob float64) {
+		is.Equal(len(picked), 3)
+		is.Equal(len(rest), 3)
+		if len(picked) == 3 && picked[0] == 0 && picked[1] == 0 && picked[2] == 0 {
+			is.True(math.Abs(prob-1.0/20) < 1e-9)
+		}
+		total += prob
+		n++
+	})
+	is.Equal(n, 6)
+	is.True(math.Abs(total-1) < 1e-9)
+}
@@ -0,0 +1,76 @@
+package probability
+
+import (
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/game"
+)
+
+// LeaveStats describe the rack a player can expect after keeping a leave
+// and drawing back up to a full rack.
+type LeaveStats struct {
+	// Draws is the number of tiles drawn to the leave.
+	Draws int `json:"draws"`
+	// Vowels[i] is the probability that the new rack has i vowels, not
+	// counting blanks.
+	Vowels []float64 `json:"vowels"`
+	// ExpectedVowels is the average number of vowels on the new rack.
+	ExpectedVowels float64 `json:"expected_vowels"`
+	// Blank is the probability that the new rack has a blank, and S that
+	// it has an S, if the alphabet has one.
+	Blank float64 `json:"blank"`
+	S     float64 `json:"s"`
+}
+
+// Leave works out the LeaveStats for keeping leave when the tiles drawn
+// come from pool. At most as many tiles are drawn as are in inBag.
+func Leave(dist *alphabet.LetterDistribution, pool *Pool,
+	leave alphabet.MachineWord, inBag int) *LeaveStats {
+
+	alph := dist.Alphabet()
+	n := game.RackTileLimit - len(leave)
+	if n > inBag {
+		n = inBag
+	}
+	n = pool.draws(n)
+
+	isVowel := map[alphabet.MachineLetter]bool{}
+	for _, r := range dist.Vowels {
+		if ml, err := alph.Val(r); err == nil {
+			isVowel[ml] = true
+		}
+	}
+	kept, poolVowels := 0, 0
+	for _, ml := range leave {
+		if isVowel[ml] {
+			kept++
+		}
+	}
+	for ml := range isVowel {
+		poolVowels += pool.counts[ml]
+	}
+
+	stats := &LeaveStats{Draws: n, Vowels: make([]float64, kept+n+1)}
+	all := choose(pool.total, n)
+	for i := 0; i <= n; i++ {
+		prob := choose(poolVowels, i) * choose(pool.total-poolVowels, n-i) / all
+		stats.Vowels[kept+i] = prob
+		stats.ExpectedVowels += float64(kept+i) * prob
+	}
+	stats.Blank = holding(pool, leave, alphabet.BlankMachineLetter, n)
+	if s, err := alph.Val('S'); err == nil {
+		stats.S = holding(pool, leave, s, n)
+	}
+	return stats
+}
+
+// holding is the probability of having ml after drawing n tiles to leave.
+func holding(pool *Pool, leave alphabet.MachineWord, ml alphabet.MachineLetter,
+	n int) float64 {
+
+	for _, l := range leave {
+		if normalize(l) == ml {
+			return 1
+		}
+	}
+	return pool.ProbabilityOfAny(alphabet.MachineWord{ml}, n)
+}
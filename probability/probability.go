@@ -0,0 +1,179 @@
+// Package probability works out exact probabilities of drawing tiles
+// from a pool, such as the tiles unseen by a player. Draws are taken to be
+// uniformly random from the whole pool, which is the best a player can do
+// without knowing which of the unseen tiles are on the opponent's rack.
+package probability
+
+import (
+	"sort"
+
+	"github.com/domino14/cwgame/alphabet"
+)
+
+// A Pool is a collection of tiles that can be drawn from.
+type Pool struct {
+	counts map[alphabet.MachineLetter]int
+	// letters are the different letters in the pool, in order.
+	letters []alphabet.MachineLetter
+	total   int
+}
+
+// NewPool makes a pool of the given tiles. Blanks that have been given a
+// letter count as blanks.
+func NewPool(tiles alphabet.MachineWord) *Pool {
+	p := &Pool{counts: map[alphabet.MachineLetter]int{}}
+	for _, ml := range tiles {
+		ml = normalize(ml)
+		if p.counts[ml] == 0 {
+			p.letters = append(p.letters, ml)
+		}
+		p.counts[ml]++
+		p.total++
+	}
+	sort.Slice(p.letters, func(i, j int) bool { return p.letters[i] < p.letters[j] })
+	return p
+}
+
+func normalize(ml alphabet.MachineLetter) alphabet.MachineLetter {
+	if ml.IsBlanked() {
+		return alphabet.BlankMachineLetter
+	}
+	return ml
+}
+
+// Size is the number of tiles in the pool.
+func (p *Pool) Size() int {
+	return p.total
+}
+
+// Count is the number of a letter in the pool.
+func (p *Pool) Count(ml alphabet.MachineLetter) int {
+	return p.counts[normalize(ml)]
+}
+
+// choose is the binomial coefficient, which is exact in a float64 for any
+// pool of tiles.
+func choose(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	c := 1.0
+	for i := 1; i <= k; i++ {
+		c = c * float64(n-k+i) / float64(i)
+	}
+	return c
+}
+
+// draws clamps the number of tiles drawn to the size of the pool.
+func (p *Pool) draws(n int) int {
+	if n > p.total {
+		return p.total
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// ProbabilityOfAny returns the probability that n tiles drawn from the
+// pool include at least one of the given letters, like a blank or an S.
+func (p *Pool) ProbabilityOfAny(letters alphabet.MachineWord, n int) float64 {
+	n = p.draws(n)
+	k := 0
+	counted := map[alphabet.MachineLetter]bool{}
+	for _, ml := range letters {
+		ml = normalize(ml)
+		if !counted[ml] {
+			counted[ml] = true
+			k += p.counts[ml]
+		}
+	}
+	return 1 - choose(p.total-k, n)/choose(p.total, n)
+}
+
+// ProbabilityOfAll returns the probability that n tiles drawn from the
+// pool include all of the given tiles; for example, with tiles EE at
+// least two Es must be drawn.
+func (p *Pool) ProbabilityOfAll(tiles alphabet.MachineWord, n int) float64 {
+	n = p.draws(n)
+	need := map[alphabet.MachineLetter]int{}
+	for _, ml := range tiles {
+		need[normalize(ml)]++
+	}
+	// ways[j] is the number of ways of drawing j tiles of the letters
+	// needed, with enough of each one.
+	ways := []float64{1}
+	rest := p.total
+	for ml, r := range need {
+		c := p.counts[ml]
+		if c < r {
+			return 0
+		}
+		rest -= c
+		next := make([]float64, len(ways)+c)
+		for j, w := range ways {
+			if w == 0 {
+				continue
+			}
+			for k := r; k <= c && j+k <= n; k++ {
+				next[j+k] += w * choose(c, k)
+			}
+		}
+		ways = next
+	}
+	favourable := 0.0
+	for j, w := range ways {
+		favourable += w * choose(rest, n-j)
+	}
+	return favourable / choose(p.total, n)
+}
+
+// ForEachDraw calls fn with every different set of n tiles that can be
+// drawn from the pool, in alphabet order, and the probability of drawing
+// it. The draw passed to fn is reused between calls.
+func (p *Pool) ForEachDraw(n int, fn func(draw alphabet.MachineWord, prob float64)) {
+	n = p.draws(n)
+	all := choose(p.total, n)
+	draw := make(alphabet.MachineWord, 0, n)
+	var visit func(i, left int, ways float64)
+	visit = func(i, left int, ways float64) {
+		if left == 0 {
+			fn(draw, ways/all)
+			return
+		}
+		if i == len(p.letters) {
+			return
+		}
+		ml := p.letters[i]
+		c := p.counts[ml]
+		for k := 0; k <= c && k <= left; k++ {
+			visit(i+1, left-k, ways*choose(c, k))
+			draw = append(draw, ml)
+		}
+		// Take off the copies of ml that were added.
+		for k := 0; k <= c && k <= left; k++ {
+			draw = draw[:len(draw)-1]
+		}
+	}
+	visit(0, n, 1)
+}
+
+// ProbabilityOf returns the probability that the rack made by adding n
+// tiles from the pool to the leave is one that pred accepts. It looks at
+// every possible draw, so pred should be quick.
+func (p *Pool) ProbabilityOf(leave alphabet.MachineWord, n int,
+	pred func(rack alphabet.MachineWord) bool) float64 {
+
+	rack := make(alphabet.MachineWord, 0, len(leave)+n)
+	total := 0.0
+	p.ForEachDraw(n, func(draw alphabet.MachineWord, prob float64) {
+		rack = append(append(rack[:0], leave...), draw...)
+		if pred(rack) {
+			total += prob
+		}
+	})
+	return total
+}
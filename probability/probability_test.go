@@ -0,0 +1,126 @@
+package probability
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func fullBag(t *testing.T) (*alphabet.LetterDistribution, alphabet.MachineWord) {
+	dist, err := alphabet.EnglishLetterDistribution(&DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := board.MakeBoard(board.CrosswordGameBoard)
+	u, err := game.UnseenTiles(dist, b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dist, u.Tiles
+}
+
+func word(t *testing.T, dist *alphabet.LetterDistribution, s string) alphabet.MachineWord {
+	w, err := alphabet.ToMachineWord(s, dist.Alphabet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestProbabilityOfAny(t *testing.T) {
+	is := is.New(t)
+	dist, tiles := fullBag(t)
+	p := NewPool(tiles)
+	is.Equal(p.Size(), 100)
+	is.Equal(p.Count(alphabet.BlankMachineLetter), 2)
+
+	// 1 - C(98,7)/C(100,7)
+	is.True(approx(p.ProbabilityOfAny(word(t, dist, "?"), 7), 1-93.0*92/(100*99)))
+	is.True(approx(p.ProbabilityOfAny(word(t, dist, "?"), 0), 0))
+	is.True(approx(p.ProbabilityOfAny(word(t, dist, "?"), 200), 1))
+	// There are six blanks and Ss, asking for S twice changes nothing.
+	is.True(approx(p.ProbabilityOfAny(word(t, dist, "?SS"), 1), 0.06))
+	is.True(approx(p.ProbabilityOfAny(word(t, dist, "Z"), 1), 0.01))
+}
+
+func TestProbabilityOfAll(t *testing.T) {
+	is := is.New(t)
+	dist, tiles := fullBag(t)
+	p := NewPool(tiles)
+
+	// C(98,5)/C(100,7)
+	is.True(approx(p.ProbabilityOfAll(word(t, dist, "??"), 7), 42.0/9900))
+	is.Equal(p.ProbabilityOfAll(word(t, dist, "???"), 7), 0.0)
+	is.True(approx(p.ProbabilityOfAll(nil, 7), 1))
+	is.True(approx(p.ProbabilityOfAll(word(t, dist, "QU"), 2), 4.0/choose(100, 2)))
+	// Needing one S is the same as needing any S.
+	is.True(approx(p.ProbabilityOfAll(word(t, dist, "S"), 7),
+		p.ProbabilityOfAny(word(t, dist, "S"), 7)))
+}
+
+func TestForEachDraw(t *testing.T) {
+	is := is.New(t)
+	dist, _ := fullBag(t)
+	p := NewPool(word(t, dist, "AAEEIQRSTT??"))
+
+	total, draws := 0.0, 0
+	p.ForEachDraw(3, func(draw alphabet.MachineWord, prob float64) {
+		is.Equal(len(draw), 3)
+		total += prob
+		draws++
+	})
+	is.True(approx(total, 1))
+	// Every way of taking three of eight different letters, with up to two
+	// each of A, E, T and ?.
+	is.Equal(draws, 84)
+
+	hasBlank := func(rack alphabet.MachineWord) bool {
+		for _, ml := range rack {
+			if ml == alphabet.BlankMachineLetter {
+				return true
+			}
+		}
+		return false
+	}
+	is.True(approx(p.ProbabilityOf(nil, 3, hasBlank),
+		p.ProbabilityOfAny(word(t, dist, "?"), 3)))
+	is.True(approx(p.ProbabilityOf(word(t, dist, "?"), 3, hasBlank), 1))
+}
+
+func TestLeave(t *testing.T) {
+	is := is.New(t)
+	dist, tiles := fullBag(t)
+	p := NewPool(tiles)
+
+	stats := Leave(dist, p, word(t, dist, "ERS"), 86)
+	is.Equal(stats.Draws, 4)
+	is.Equal(len(stats.Vowels), 6)
+	is.Equal(stats.Vowels[0], 0.0)
+	is.True(stats.Vowels[1] > 0)
+	sum := 0.0
+	for _, v := range stats.Vowels {
+		sum += v
+	}
+	is.True(approx(sum, 1))
+	// 42 of the 100 tiles are vowels.
+	is.True(approx(stats.ExpectedVowels, 1+4*0.42))
+	is.Equal(stats.S, 1.0)
+	is.True(approx(stats.Blank, p.ProbabilityOfAny(word(t, dist, "?"), 4)))
+
+	// Only two tiles are left to draw.
+	stats = Leave(dist, p, word(t, dist, "ERS"), 2)
+	is.Equal(stats.Draws, 2)
+	is.Equal(len(stats.Vowels), 4)
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+)
+
+// Image draws the board into an image. Text is drawn with a small bitmap
+// font, so letters that aren't in it show up as boxes.
+func Image(b *board.GameBoard, dist *alphabet.LetterDistribution, opts *Options) (*image.RGBA, error) {
+	d, err := layout(b, dist, opts)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+	for _, r := range d.rects {
+		bounds := image.Rect(r.x, r.y, r.x+r.w, r.y+r.h)
+		draw.Draw(img, bounds, image.NewUniform(r.fill), image.Point{}, draw.Src)
+		if r.stroke != nil {
+			outline(img, bounds, *r.stroke)
+		}
+	}
+	for _, t := range d.texts {
+		drawText(img, t)
+	}
+	return img, nil
+}
+
+// PNG writes a PNG image of the board.
+func PNG(w io.Writer, b *board.GameBoard, dist *alphabet.LetterDistribution, opts *Options) error {
+	img, err := Image(b, dist, opts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+func outline(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		img.SetRGBA(x, r.Min.Y, c)
+		img.SetRGBA(x, r.Max.Y-1, c)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		img.SetRGBA(r.Min.X, y, c)
+		img.SetRGBA(r.Max.X-1, y, c)
+	}
+}
+
+func drawText(img *image.RGBA, t text) {
+	scale := glyphScale(t.size)
+	x := t.x
+	switch t.anchor {
+	case anchorMiddle:
+		x -= textWidth(t.s, t.size) / 2
+	case anchorEnd:
+		x -= textWidth(t.s, t.size)
+	}
+	top := t.y - glyphHeight*scale
+	for _, r := range t.s {
+		g := glyph(r)
+		for row, bits := range g {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := image.Rect(x+col*scale, top+row*scale,
+					x+(col+1)*scale, top+(row+1)*scale)
+				if t.bold {
+					// Thicken the strokes by a pixel.
+					px.Max.X++
+				}
+				draw.Draw(img, px, image.NewUniform(t.color), image.Point{}, draw.Src)
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
@@ -0,0 +1,279 @@
+// Package render draws board positions as SVG or PNG images, with bonus
+// squares, tile values, coordinates and the last move highlighted, and
+// optionally a rack and the players' scores below the board.
+package render
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+)
+
+// DefaultSquareSize is the size of a square, in pixels, if the options
+// don't give one.
+const DefaultSquareSize = 32
+
+// A Player is shown in the score panel.
+type Player struct {
+	Name   string
+	Score  int
+	OnTurn bool
+}
+
+// Options control what is drawn besides the board.
+type Options struct {
+	// SquareSize is the size of a square in pixels.
+	SquareSize int
+	// Coordinates draws the column letters and row numbers.
+	Coordinates bool
+	// LastMove is highlighted if it is a play.
+	LastMove *move.Move
+	// Rack is drawn below the board if it is not empty.
+	Rack string
+	// Players are drawn below the board, with their scores.
+	Players []Player
+}
+
+// OptionsForGame returns options that show the last move of the game, the
+// rack of the player on turn and the scores.
+func OptionsForGame(g *game.Game) *Options {
+	opts := &Options{Coordinates: true}
+	// The game may have been played to an earlier turn than the last
+	// one in its history.
+	if t := g.Turn(); t > 0 && t <= len(g.History().Events) {
+		evt := g.History().Events[t-1]
+		if evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE {
+			opts.LastMove = playFromEvent(evt, g.Alphabet())
+		}
+	}
+	if g.Playing() != pb.PlayState_GAME_OVER {
+		opts.Rack = g.RackLettersFor(g.PlayerOnTurn())
+	}
+	for i, p := range g.History().Players {
+		opts.Players = append(opts.Players, Player{
+			Name:   p.Nickname,
+			Score:  g.PointsFor(i),
+			OnTurn: g.Playing() != pb.PlayState_GAME_OVER && g.PlayerOnTurn() == i,
+		})
+	}
+	return opts
+}
+
+// playFromEvent makes the play of an event that is already on the board.
+// game.MoveFromEvent can't be used, as it works out the tiles played
+// through from the board before the play.
+func playFromEvent(evt *pb.GameEvent, alph *alphabet.Alphabet) *move.Move {
+	tiles, err := alphabet.ToMachineWord(evt.PlayedTiles, alph)
+	if err != nil {
+		return nil
+	}
+	tilesPlayed := 0
+	for _, t := range tiles {
+		if t != alphabet.PlayedThroughMarker {
+			tilesPlayed++
+		}
+	}
+	return move.NewScoringMove(int(evt.Score), tiles, nil,
+		evt.Direction == pb.GameEvent_VERTICAL, tilesPlayed, alph,
+		int(evt.Row), int(evt.Column), evt.Position)
+}
+
+var (
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	squareColor     = color.RGBA{0xf4, 0xf1, 0xe4, 0xff}
+	lineColor       = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	tileColor       = color.RGBA{0xf7, 0xd7, 0x74, 0xff}
+	lastMoveColor   = color.RGBA{0xff, 0xb8, 0x4d, 0xff}
+	letterColor     = color.RGBA{0x22, 0x22, 0x22, 0xff}
+	blankColor      = color.RGBA{0xb0, 0x30, 0x30, 0xff}
+	labelColor      = color.RGBA{0x66, 0x66, 0x66, 0xff}
+	bonusColors     = map[board.BonusSquare]color.RGBA{
+		board.Bonus3WS: {0xe5, 0x53, 0x4b, 0xff},
+		board.Bonus2WS: {0xf2, 0xa7, 0xa3, 0xff},
+		board.Bonus3LS: {0x3b, 0x82, 0xc4, 0xff},
+		board.Bonus2LS: {0xa8, 0xd0, 0xef, 0xff},
+	}
+	bonusLabels = map[board.BonusSquare]string{
+		board.Bonus3WS: "TW",
+		board.Bonus2WS: "DW",
+		board.Bonus3LS: "TL",
+		board.Bonus2LS: "DL",
+	}
+)
+
+// A rect is a filled rectangle with an optional outline.
+type rect struct {
+	x, y, w, h int
+	fill       color.RGBA
+	stroke     *color.RGBA
+}
+
+type anchor int
+
+const (
+	anchorMiddle anchor = iota
+	anchorStart
+	anchorEnd
+)
+
+// A text is drawn with its baseline at y, and aligned at x by its anchor.
+type text struct {
+	x, y   int
+	size   int
+	s      string
+	color  color.RGBA
+	anchor anchor
+	bold   bool
+}
+
+// A drawing is the shapes of an image, in the order they are drawn. It is
+// made once and then written out as SVG or rasterized.
+type drawing struct {
+	width, height int
+	rects         []rect
+	texts         []text
+}
+
+func (d *drawing) rect(x, y, w, h int, fill color.RGBA, stroke *color.RGBA) {
+	d.rects = append(d.rects, rect{x, y, w, h, fill, stroke})
+}
+
+func (d *drawing) text(t text) {
+	d.texts = append(d.texts, t)
+}
+
+// layout makes the drawing of a board and the panels in the options.
+func layout(b *board.GameBoard, dist *alphabet.LetterDistribution, opts *Options) (*drawing, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	s := opts.SquareSize
+	if s <= 0 {
+		s = DefaultSquareSize
+	}
+	alph := dist.Alphabet()
+	dim := b.Dim()
+	d := &drawing{}
+
+	// The board starts after the coordinates, if there are any.
+	x0, y0 := 0, 0
+	if opts.Coordinates {
+		x0, y0 = s*3/4, s*3/4
+	}
+	d.width = x0 + dim*s
+	d.height = y0 + dim*s
+	d.rect(0, 0, 0, 0, backgroundColor, nil) // resized below
+
+	if opts.Coordinates {
+		for i := 0; i < dim; i++ {
+			d.text(text{x: x0 + i*s + s/2, y: y0 - s/4, size: s * 2 / 5,
+				s: string(rune('A' + i)), color: labelColor})
+			d.text(text{x: x0 - s/8, y: y0 + i*s + s/2 + s/7, size: s * 2 / 5,
+				s: fmt.Sprint(i + 1), color: labelColor, anchor: anchorEnd})
+		}
+	}
+
+	highlighted := map[[2]int]bool{}
+	if m := opts.LastMove; m != nil && m.Action() == move.MoveTypePlay {
+		row, col, vertical := m.CoordsAndVertical()
+		for _, t := range m.Tiles() {
+			if t != alphabet.PlayedThroughMarker {
+				highlighted[[2]int{row, col}] = true
+			}
+			if vertical {
+				row++
+			} else {
+				col++
+			}
+		}
+	}
+
+	line := lineColor
+	for r := 0; r < dim; r++ {
+		for c := 0; c < dim; c++ {
+			x, y := x0+c*s, y0+r*s
+			ml := b.GetLetter(r, c)
+			if ml == alphabet.EmptySquareMarker {
+				bonus := b.GetBonus(r, c)
+				fill, ok := bonusColors[bonus]
+				if !ok {
+					fill = squareColor
+				}
+				d.rect(x, y, s, s, fill, &line)
+				if label, ok := bonusLabels[bonus]; ok {
+					d.text(text{x: x + s/2, y: y + s/2 + s/8, size: s * 3 / 10,
+						s: label, color: backgroundColor})
+				}
+				continue
+			}
+			fill := tileColor
+			if highlighted[[2]int{r, c}] {
+				fill = lastMoveColor
+			}
+			tile(d, x, y, s, fill, &line, ml, alph, dist)
+		}
+	}
+
+	// The panels go below the board.
+	y := d.height + s/4
+	if opts.Rack != "" {
+		rack, err := alphabet.ToMachineWord(opts.Rack, alph)
+		if err != nil {
+			return nil, err
+		}
+		rackWidth := len(rack) * s * 9 / 8
+		x := x0 + (dim*s-rackWidth)/2
+		for _, ml := range rack {
+			tile(d, x, y, s, tileColor, &line, ml, alph, dist)
+			x += s * 9 / 8
+		}
+		y += s * 5 / 4
+	}
+	for _, p := range opts.Players {
+		name := p.Name
+		if p.OnTurn {
+			name = "> " + name
+		}
+		y += s * 3 / 4
+		d.text(text{x: x0, y: y, size: s / 2, s: name, color: letterColor,
+			anchor: anchorStart, bold: p.OnTurn})
+		d.text(text{x: x0 + dim*s, y: y, size: s / 2, s: fmt.Sprint(p.Score),
+			color: letterColor, anchor: anchorEnd, bold: p.OnTurn})
+	}
+	if len(opts.Players) > 0 {
+		y += s / 4
+	}
+	if y > d.height+s/4 {
+		d.height = y
+	}
+	d.rects[0].w, d.rects[0].h = d.width, d.height
+	return d, nil
+}
+
+// tile draws a tile with its letter and value. Blanks have a differently
+// coloured letter and no value.
+func tile(d *drawing, x, y, s int, fill color.RGBA, stroke *color.RGBA,
+	ml alphabet.MachineLetter, alph *alphabet.Alphabet, dist *alphabet.LetterDistribution) {
+
+	d.rect(x, y, s, s, fill, stroke)
+	letterColor := letterColor
+	if ml.IsBlanked() || ml == alphabet.BlankMachineLetter {
+		letterColor = blankColor
+	}
+	letter := string(ml.UserVisible(alph))
+	if ml.IsBlanked() {
+		letter = string(ml.Unblank().UserVisible(alph))
+	}
+	d.text(text{x: x + s*9/20, y: y + s*7/10, size: s * 3 / 5, s: letter,
+		color: letterColor, bold: true})
+	if ml.IsBlanked() || ml == alphabet.BlankMachineLetter {
+		return
+	}
+	d.text(text{x: x + s - s/12, y: y + s - s/10, size: s / 4,
+		s: fmt.Sprint(dist.Score(ml)), color: letterColor, anchor: anchorEnd})
+}
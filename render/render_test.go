@@ -0,0 +1,84 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func gameAt(t *testing.T, turn int) *game.Game {
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := game.NewFromHistory(h, rules, turn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestSVG(t *testing.T) {
+	is := is.New(t)
+	// After K5 RE.IgION, which has a blank.
+	g := gameAt(t, 18)
+	opts := OptionsForGame(g)
+	is.Equal(opts.Rack, g.RackLettersFor(g.PlayerOnTurn()))
+	is.Equal(len(opts.Players), 2)
+
+	var out bytes.Buffer
+	is.NoErr(SVG(&out, g.Board(), g.Bag().LetterDistribution(), opts))
+	svg := out.String()
+	is.True(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="504" height="`))
+	is.True(strings.HasSuffix(svg, "</svg>\n"))
+	// The blank is a G in another colour.
+	is.True(strings.Contains(svg, `fill="#b03030" text-anchor="middle" font-weight="bold">G</text>`))
+	// The tiles just played are highlighted.
+	is.Equal(strings.Count(svg, `fill="#ffb84d"`), 7)
+	// Coordinates.
+	is.True(strings.Contains(svg, `>O</text>`))
+	is.True(strings.Contains(svg, `>15</text>`))
+	is.True(strings.Contains(svg, `>335</text>`))
+}
+
+func TestPNG(t *testing.T) {
+	is := is.New(t)
+	g := gameAt(t, 1)
+	opts := &Options{SquareSize: 20, LastMove: OptionsForGame(g).LastMove}
+
+	var out bytes.Buffer
+	is.NoErr(PNG(&out, g.Board(), g.Bag().LetterDistribution(), opts))
+	img, err := png.Decode(&out)
+	is.NoErr(err)
+	is.Equal(img.Bounds().Dx(), 300)
+	is.Equal(img.Bounds().Dy(), 300)
+
+	// 8D WINDY is highlighted; the corner of the W square is clear of the
+	// letter and its value.
+	r, gr, b, _ := img.At(3*20+2, 7*20+2).RGBA()
+	is.Equal([]uint32{r >> 8, gr >> 8, b >> 8}, []uint32{0xff, 0xb8, 0x4d})
+	// A1 is a triple word score.
+	r, gr, b, _ = img.At(2, 2).RGBA()
+	is.Equal([]uint32{r >> 8, gr >> 8, b >> 8}, []uint32{0xe5, 0x53, 0x4b})
+}
+
+func TestBadRack(t *testing.T) {
+	is := is.New(t)
+	g := gameAt(t, 0)
+	err := SVG(&bytes.Buffer{}, g.Board(), g.Bag().LetterDistribution(), &Options{Rack: "AB3"})
+	is.True(err != nil)
+}
@@ -0,0 +1,54 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+)
+
+// SVG writes an SVG image of the board.
+func SVG(w io.Writer, b *board.GameBoard, dist *alphabet.LetterDistribution, opts *Options) error {
+	d, err := layout(b, dist, opts)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		d.width, d.height, d.width, d.height)
+	for _, r := range d.rects {
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"`,
+			r.x, r.y, r.w, r.h, hex(r.fill))
+		if r.stroke != nil {
+			fmt.Fprintf(bw, ` stroke="%s"`, hex(*r.stroke))
+		}
+		bw.WriteString("/>\n")
+	}
+	for _, t := range d.texts {
+		anchor := "middle"
+		switch t.anchor {
+		case anchorStart:
+			anchor = "start"
+		case anchorEnd:
+			anchor = "end"
+		}
+		weight := ""
+		if t.bold {
+			weight = ` font-weight="bold"`
+		}
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-size="%d" fill="%s" text-anchor="%s"%s>`,
+			t.x, t.y, t.size, hex(t.color), anchor, weight)
+		xml.EscapeText(bw, []byte(t.s))
+		bw.WriteString("</text>\n")
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
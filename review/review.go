@@ -0,0 +1,327 @@
+// Package review goes through a finished game and compares every play and
+// exchange with the best move that could have been made instead.
+package review
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/montecarlo"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+// DefaultSimCandidates is the number of top moves simulated along with
+// the move that was made.
+const DefaultSimCandidates = 5
+
+// A Turn is the review of one play or exchange.
+type Turn struct {
+	// Event is the index of the event in the history.
+	Event      int     `json:"event"`
+	Nickname   string  `json:"nickname"`
+	Rack       string  `json:"rack"`
+	Played     string  `json:"played"`
+	Score      int     `json:"score"`
+	Equity     float64 `json:"equity"`
+	Best       string  `json:"best"`
+	BestScore  int     `json:"bestScore"`
+	BestEquity float64 `json:"bestEquity"`
+	// EquityLoss is how much less equity the move had than the best
+	// one; it is never negative.
+	EquityLoss float64 `json:"equityLoss"`
+	// MissedBingo is the best bingo, if there was one and the move
+	// wasn't a bingo.
+	MissedBingo string `json:"missedBingo,omitempty"`
+	// Phonies are the invalid words the play formed that were not
+	// challenged off.
+	Phonies []string `json:"phonies,omitempty"`
+	// Withdrawn is true if the play was challenged off.
+	Withdrawn bool `json:"withdrawn,omitempty"`
+}
+
+// PlayerSummary adds up the turns of one player.
+type PlayerSummary struct {
+	Nickname      string  `json:"nickname"`
+	Turns         int     `json:"turns"`
+	EquityLoss    float64 `json:"equityLoss"`
+	AverageLoss   float64 `json:"averageLoss"`
+	BestMoves     int     `json:"bestMoves"`
+	MissedBingos  int     `json:"missedBingos"`
+	PhoniesPlayed int     `json:"phoniesPlayed"`
+}
+
+// A Report is the review of a whole game.
+type Report struct {
+	// Simulated is true if the equities are the mean spreads of
+	// simulations rather than static equities.
+	Simulated bool             `json:"simulated"`
+	Turns     []*Turn          `json:"turns"`
+	Players   []*PlayerSummary `json:"players"`
+}
+
+// Reviewer reviews games.
+type Reviewer struct {
+	gen  movegen.MoveGenerator
+	calc equity.Calculator
+	lex  lexicon.Lexicon
+	// Sim, if not nil, simulates the top SimCandidates moves and the
+	// move that was made, instead of comparing their static equity.
+	Sim           *montecarlo.Simmer
+	SimCandidates int
+	// Stop says when each simulation stops.
+	Stop montecarlo.StopCondition
+}
+
+// NewReviewer creates a Reviewer that finds moves with gen and ranks
+// them with calc. Phonies are found with lex; if it is nil, no words
+// are taken to be phonies.
+func NewReviewer(gen movegen.MoveGenerator, calc equity.Calculator, lex lexicon.Lexicon) *Reviewer {
+	return &Reviewer{gen: gen, calc: calc, lex: lex, SimCandidates: DefaultSimCandidates,
+		Stop: montecarlo.StopCondition{Iterations: 200}}
+}
+
+// Review reviews every play and exchange in the history whose rack is
+// known. The history is not changed.
+func (r *Reviewer) Review(ctx context.Context, h *pb.GameHistory, rules *game.GameRules) (*Report, error) {
+	g, err := game.ReplayHistory(h, rules, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Simulated: r.Sim != nil}
+	summaries := map[string]*PlayerSummary{}
+	for _, p := range h.Players {
+		s := &PlayerSummary{Nickname: p.Nickname}
+		summaries[p.Nickname] = s
+		report.Players = append(report.Players, s)
+	}
+	for i, evt := range h.Events {
+		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE && evt.Type != pb.GameEvent_EXCHANGE ||
+			evt.Rack == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := g.PlayToTurn(i); err != nil {
+			return nil, err
+		}
+		withdrawn := i+1 < len(h.Events) &&
+			h.Events[i+1].Type == pb.GameEvent_PHONY_TILES_RETURNED
+		turn, err := r.review(ctx, g, evt, withdrawn)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %v", i, err)
+		}
+		turn.Event = i
+		report.Turns = append(report.Turns, turn)
+
+		if s, ok := summaries[evt.Nickname]; ok {
+			s.Turns++
+			s.EquityLoss += turn.EquityLoss
+			if turn.EquityLoss == 0 {
+				s.BestMoves++
+			}
+			if turn.MissedBingo != "" {
+				s.MissedBingos++
+			}
+			if len(turn.Phonies) > 0 {
+				s.PhoniesPlayed++
+			}
+		}
+	}
+	for _, s := range report.Players {
+		if s.Turns > 0 {
+			s.AverageLoss = s.EquityLoss / float64(s.Turns)
+		}
+	}
+	return report, nil
+}
+
+// review compares the move made in evt with the moves the player could
+// have made, with g at that turn.
+func (r *Reviewer) review(ctx context.Context, g *game.Game, evt *pb.GameEvent,
+	withdrawn bool) (*Turn, error) {
+
+	alph := g.Alphabet()
+	played := game.MoveFromEvent(evt, alph, g.Board())
+	if played == nil {
+		return nil, fmt.Errorf("cannot make a move from %v", evt.Rack)
+	}
+	rack := alphabet.RackFromString(evt.Rack, alph)
+	moves := r.gen.GenerateMoves(g.Board(), rack, g.Bag().TilesRemaining())
+	equity.Rank(moves, r.calc, g.Board(), g.Bag(), nil)
+
+	turn := &Turn{
+		Nickname:  evt.Nickname,
+		Rack:      evt.Rack,
+		Played:    played.ShortDescription(),
+		Score:     played.Score(),
+		Withdrawn: withdrawn,
+	}
+	// Use the generated move if there is one, so that it is ranked the
+	// same way as the others.
+	found := false
+	for _, m := range moves {
+		if same(m, played) {
+			played, found = m, true
+			break
+		}
+	}
+	if !found {
+		played.SetEquity(r.calc.Equity(played, g.Board(), g.Bag(), nil))
+	}
+	if played.Action() == move.MoveTypePlay {
+		words, err := g.Board().FormedWords(played)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range words {
+			if !withdrawn && r.lex != nil && !r.lex.HasWord(w) {
+				turn.Phonies = append(turn.Phonies, w.UserVisible(alph))
+			}
+		}
+	}
+	if played.TilesPlayed() < game.RackTileLimit || played.Action() != move.MoveTypePlay {
+		for _, m := range moves {
+			if m.Action() == move.MoveTypePlay && m.TilesPlayed() == game.RackTileLimit {
+				turn.MissedBingo = m.ShortDescription()
+				break
+			}
+		}
+	}
+
+	if r.Sim != nil {
+		var err error
+		if moves, err = r.simulate(ctx, g, moves, played); err != nil {
+			return nil, err
+		}
+	}
+	best := played
+	if len(moves) > 0 && moves[0].Equity() > played.Equity() {
+		best = moves[0]
+	}
+	turn.Equity = played.Equity()
+	turn.Best = best.ShortDescription()
+	turn.BestScore = best.Score()
+	turn.BestEquity = best.Equity()
+	turn.EquityLoss = best.Equity() - played.Equity()
+	return turn, nil
+}
+
+// simulate simulates the top moves and the one that was played, setting
+// their equity to their mean spread. It returns the moves simulated,
+// best first.
+func (r *Reviewer) simulate(ctx context.Context, g *game.Game, moves []*move.Move,
+	played *move.Move) ([]*move.Move, error) {
+
+	candidates := []*move.Move{}
+	for _, m := range moves {
+		if len(candidates) == r.SimCandidates {
+			break
+		}
+		if m != played {
+			candidates = append(candidates, m)
+		}
+	}
+	candidates = append(candidates, played)
+	results, err := r.Sim.Simulate(ctx, g, candidates, r.Stop)
+	if err != nil {
+		return nil, err
+	}
+	simmed := []*move.Move{}
+	for _, c := range results {
+		c.Move.SetEquity(c.Spread.Mean())
+		simmed = append(simmed, c.Move)
+	}
+	sort.SliceStable(simmed, func(i, j int) bool {
+		return simmed[i].Equity() > simmed[j].Equity()
+	})
+	return simmed, nil
+}
+
+// same returns true if a and b are the same move.
+func same(a, b *move.Move) bool {
+	if a.Action() != b.Action() {
+		return false
+	}
+	switch a.Action() {
+	case move.MoveTypePlay:
+		return a.BoardCoords() == b.BoardCoords() && a.Tiles().String() == b.Tiles().String()
+	case move.MoveTypeExchange:
+		return sorted(a.Tiles()) == sorted(b.Tiles())
+	}
+	return true
+}
+
+func sorted(tiles alphabet.MachineWord) string {
+	s := append(alphabet.MachineWord{}, tiles...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s.String()
+}
+
+// Note describes the turn in a sentence or two, for the notes of its
+// event.
+func (t *Turn) Note() string {
+	parts := []string{}
+	if t.EquityLoss == 0 {
+		parts = append(parts, fmt.Sprintf("Review: %v was the best move (equity %.1f).",
+			t.Played, t.Equity))
+	} else {
+		parts = append(parts, fmt.Sprintf("Review: best was %v for %d (equity %.1f); %v lost %.1f.",
+			t.Best, t.BestScore, t.BestEquity, t.Played, t.EquityLoss))
+	}
+	if t.MissedBingo != "" {
+		parts = append(parts, fmt.Sprintf("Missed bingo: %v.", t.MissedBingo))
+	}
+	if len(t.Phonies) > 0 {
+		parts = append(parts, fmt.Sprintf("Unchallenged phonies: %v.", strings.Join(t.Phonies, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Annotation returns the turn as an annotation, with the best move and
+// the move made as its candidates.
+func (t *Turn) Annotation() *pb.Annotation {
+	a := &pb.Annotation{Candidates: []*pb.CandidatePlay{
+		{Description: t.Best, Score: int32(t.BestScore), Equity: t.BestEquity},
+	}}
+	if t.EquityLoss == 0 {
+		a.Tags = append(a.Tags, "best move")
+	} else {
+		a.Candidates = append(a.Candidates, &pb.CandidatePlay{
+			Description: t.Played, Score: int32(t.Score), Equity: t.Equity})
+	}
+	if t.MissedBingo != "" {
+		a.Tags = append(a.Tags, "missed bingo")
+	}
+	if len(t.Phonies) > 0 {
+		a.Tags = append(a.Tags, "phony")
+	}
+	return a
+}
+
+// Annotate adds the note of every turn to the notes of its event in h,
+// and replaces the annotation of the event with the turn's. h must be
+// the history that was reviewed.
+func (r *Report) Annotate(h *pb.GameHistory) error {
+	for _, t := range r.Turns {
+		if t.Event >= len(h.Events) || h.Events[t.Event].Nickname != t.Nickname {
+			return fmt.Errorf("the report is not for this history")
+		}
+		evt := h.Events[t.Event]
+		if evt.Note != "" {
+			evt.Note += "\n"
+		}
+		evt.Note += t.Note()
+		evt.Annotation = t.Annotation()
+	}
+	return nil
+}
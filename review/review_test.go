@@ -0,0 +1,157 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/montecarlo"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+// setup returns doug_v_emely and a Reviewer that knows every word played
+// in it, but takes COY to be a phony.
+func setup(t *testing.T) (*pb.GameHistory, *game.GameRules, *Reviewer) {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alph := rules.LetterDistribution().Alphabet()
+	twos := []string{"QI", "ZA", "XI", "OX", "AX", "EX", "JO", "TA", "AT", "ER", "RE"}
+	words := append([]string{}, twos...)
+	valid := append([]string{}, twos...)
+	for i, evt := range h.Events {
+		if evt.Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+			continue
+		}
+		g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		formed, err := g.Board().FormedWords(game.MoveFromEvent(evt, alph, g.Board()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range formed {
+			words = append(words, w.UserVisible(alph))
+			if w.UserVisible(alph) != "COY" {
+				valid = append(valid, w.UserVisible(alph))
+			}
+		}
+	}
+	wl, err := lexicon.NewWordList("test", alph, words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lex, err := lexicon.NewWordList("check", alph, valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rules.LetterDistribution()
+	return h, rules, NewReviewer(movegen.NewTrieGenerator(wl, dist), equity.NewLeaveCalculator(dist), lex)
+}
+
+func TestReview(t *testing.T) {
+	is := is.New(t)
+	h, rules, r := setup(t)
+	orig := proto.Clone(h)
+	report, err := r.Review(context.Background(), h, rules)
+	is.NoErr(err)
+	is.True(proto.Equal(h, orig))
+	is.True(!report.Simulated)
+
+	moves := 0
+	for _, evt := range h.Events {
+		if evt.Type == pb.GameEvent_TILE_PLACEMENT_MOVE || evt.Type == pb.GameEvent_EXCHANGE {
+			moves++
+		}
+	}
+	is.Equal(len(report.Turns), moves)
+	byEvent := map[int]*Turn{}
+	for _, turn := range report.Turns {
+		is.True(turn.EquityLoss >= 0)
+		is.True(turn.BestEquity >= turn.Equity)
+		byEvent[turn.Event] = turn
+	}
+
+	donates := byEvent[4]
+	is.Equal(donates.Played, "10B DONATES")
+	is.Equal(donates.Score, 82)
+	is.Equal(donates.MissedBingo, "")
+
+	til := byEvent[5]
+	is.True(til.Withdrawn)
+	is.Equal(len(til.Phonies), 0)
+
+	coy := byEvent[20]
+	is.Equal(coy.Played, "14F COY")
+	is.Equal(coy.Phonies, []string{"COY"})
+
+	turns := 0
+	for _, s := range report.Players {
+		turns += s.Turns
+		is.True(s.BestMoves <= s.Turns)
+		if s.Nickname == "emely" {
+			is.Equal(s.PhoniesPlayed, 1)
+		}
+	}
+	is.Equal(turns, moves)
+}
+
+func TestAnnotate(t *testing.T) {
+	is := is.New(t)
+	h, rules, r := setup(t)
+	report, err := r.Review(context.Background(), h, rules)
+	is.NoErr(err)
+	is.NoErr(report.Annotate(h))
+	is.True(strings.Contains(h.Events[20].Note, "Unchallenged phonies: COY."))
+	is.True(strings.HasPrefix(h.Events[4].Note, "Review: "))
+	// COY scores best with a word list that has it.
+	is.Equal(h.Events[20].Annotation.Tags, []string{"best move", "phony"})
+	is.Equal(h.Events[20].Annotation.Candidates[0].Description, "14F COY")
+
+	gcg, err := gcgio.GameHistoryToGCG(h, false)
+	is.NoErr(err)
+	is.True(strings.Contains(gcg, "#note Review: "))
+	back, err := gcgio.ParseGCGFromReader(&DefaultConfig, strings.NewReader(gcg))
+	is.NoErr(err)
+	is.Equal(back.Events[20].Note, h.Events[20].Note)
+
+	// A report only goes with the history it was made from.
+	other := proto.Clone(h).(*pb.GameHistory)
+	other.Events = other.Events[:3]
+	is.True(report.Annotate(other) != nil)
+}
+
+func TestReviewSimulated(t *testing.T) {
+	is := is.New(t)
+	h, rules, r := setup(t)
+	h.Events = h.Events[:5]
+	r.Sim = montecarlo.NewSimmer(r.gen, r.calc)
+	r.Sim.Threads = 2
+	r.SimCandidates = 2
+	r.Stop = montecarlo.StopCondition{Iterations: 5}
+	report, err := r.Review(context.Background(), h, rules)
+	is.NoErr(err)
+	is.True(report.Simulated)
+	is.Equal(len(report.Turns), 5)
+	for _, turn := range report.Turns {
+		is.True(turn.EquityLoss >= 0)
+	}
+}
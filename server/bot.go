@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+// BotServer implements pb.BotServer. It answers with the move that has
+// the best static equity for the player on turn.
+type BotServer struct {
+	pb.UnimplementedBotServer
+
+	cfg  *config.Config
+	gen  movegen.MoveGenerator
+	calc equity.Calculator
+}
+
+// NewBotServer creates a BotServer that finds moves with gen and ranks
+// them with calc. The config is used to load the rules of each game.
+func NewBotServer(cfg *config.Config, gen movegen.MoveGenerator, calc equity.Calculator) *BotServer {
+	return &BotServer{cfg: cfg, gen: gen, calc: calc}
+}
+
+func botError(msg string) *pb.BotResponse {
+	return &pb.BotResponse{Response: &pb.BotResponse_Error{Error: msg}}
+}
+
+// GetMove replays the history and returns the move for the player on
+// turn, whose rack must be the last known one. A game that can't be
+// played from is answered with an error in the response.
+func (b *BotServer) GetMove(ctx context.Context, req *pb.BotRequest) (*pb.BotResponse, error) {
+	h := req.GameHistory
+	if h == nil || len(h.Players) != 2 {
+		return nil, status.Error(codes.InvalidArgument, "a game history with two players is required")
+	}
+	boardLayout, letterDistributionName := game.HistoryToVariant(h)
+	rules, err := game.NewBasicGameRules(b.cfg, boardLayout, letterDistributionName)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	g, err := game.ReplayHistory(h, rules, len(h.Events))
+	if err != nil {
+		return botError(err.Error()), nil
+	}
+	onturn := g.PlayerOnTurn()
+	switch {
+	case g.Playing() == pb.PlayState_GAME_OVER:
+		return botError("the game is over"), nil
+	case len(h.LastKnownRacks) <= onturn || h.LastKnownRacks[onturn] == "":
+		return botError("the rack of the player on turn is not known"), nil
+	case g.Playing() == pb.PlayState_WAITING_FOR_FINAL_PASS:
+		pass := move.NewPassMove(g.RackFor(onturn).TilesOn(), g.Alphabet())
+		return &pb.BotResponse{Response: &pb.BotResponse_Move{Move: g.EventFromMove(pass)}}, nil
+	}
+
+	moves := b.gen.GenerateMoves(g.Board(), g.RackFor(onturn), g.Bag().TilesRemaining())
+	equity.Rank(moves, b.calc, g.Board(), g.Bag(), nil)
+	return &pb.BotResponse{Response: &pb.BotResponse_Move{Move: g.EventFromMove(moves[0])}}, nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/movegen"
+)
+
+func TestGetMove(t *testing.T) {
+	is := is.New(t)
+	dist, err := alphabet.EnglishLetterDistribution(&DefaultConfig)
+	is.NoErr(err)
+	wl, err := lexicon.NewWordList("test", dist.Alphabet(),
+		[]string{"WINDY", "GALE", "JAVELIN", "JAVE", "JANES", "NAVES"})
+	is.NoErr(err)
+	b := NewBotServer(&DefaultConfig, movegen.NewTrieGenerator(wl, dist),
+		equity.NewLeaveCalculator(dist))
+	pb.RegisterBotServer(grpc.NewServer(), b)
+	ctx := context.Background()
+
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	is.NoErr(err)
+	full := h.Events
+	// doug to play, with AEJNOSV.
+	h.Events = full[:2]
+	h.LastKnownRacks = []string{"AEJNOSV", ""}
+	resp, err := b.GetMove(ctx, &pb.BotRequest{GameHistory: h})
+	is.NoErr(err)
+	evt := resp.GetMove()
+	is.True(evt != nil)
+	is.Equal(evt.Nickname, "doug")
+	is.Equal(evt.Type, pb.GameEvent_TILE_PLACEMENT_MOVE)
+	is.Equal(evt.Rack, "AEJNOSV")
+
+	h.LastKnownRacks = []string{"", ""}
+	resp, err = b.GetMove(ctx, &pb.BotRequest{GameHistory: h})
+	is.NoErr(err)
+	is.Equal(resp.GetError(), "the rack of the player on turn is not known")
+
+	h.Events = full
+	resp, err = b.GetMove(ctx, &pb.BotRequest{GameHistory: h})
+	is.NoErr(err)
+	is.Equal(resp.GetError(), "the game is over")
+
+	_, err = b.GetMove(ctx, &pb.BotRequest{})
+	is.Equal(status.Code(err), codes.InvalidArgument)
+}
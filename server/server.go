@@ -0,0 +1,253 @@
+// Package server implements the gRPC GameService on top of game.Game, so
+// that other services can create games, submit moves and follow along
+// without having to link this module's game logic directly. It also
+// implements the Bot service, which suggests a move for any position.
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+)
+
+// liveGame is a game in progress, along with anyone listening to its events.
+type liveGame struct {
+	sync.Mutex
+	game *game.Game
+	// subscribers get a non-blocking notification every time new events
+	// are added to the game's history.
+	subscribers map[chan struct{}]bool
+}
+
+func (lg *liveGame) subscribe() chan struct{} {
+	lg.Lock()
+	defer lg.Unlock()
+	ch := make(chan struct{}, 1)
+	lg.subscribers[ch] = true
+	return ch
+}
+
+func (lg *liveGame) unsubscribe(ch chan struct{}) {
+	lg.Lock()
+	defer lg.Unlock()
+	delete(lg.subscribers, ch)
+}
+
+// notify must be called with the lock held.
+func (lg *liveGame) notify() {
+	for ch := range lg.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// There is already a pending notification; the subscriber
+			// will pick up all new events when it gets to it.
+		}
+	}
+}
+
+// GameServer implements pb.GameServiceServer. Games are kept in memory,
+// keyed by the Uid of their history.
+type GameServer struct {
+	pb.UnimplementedGameServiceServer
+
+	sync.Mutex
+	cfg   *config.Config
+	games map[string]*liveGame
+}
+
+// NewGameServer creates a GameServer. The config is used to load the
+// letter distributions for new games.
+func NewGameServer(cfg *config.Config) *GameServer {
+	return &GameServer{
+		cfg:   cfg,
+		games: make(map[string]*liveGame),
+	}
+}
+
+func (s *GameServer) lookup(id string) (*liveGame, error) {
+	s.Lock()
+	defer s.Unlock()
+	lg, ok := s.games[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "game %v not found", id)
+	}
+	return lg, nil
+}
+
+// NewGame starts a brand new game and returns its history. The Uid of the
+// returned history is the ID with which the game should be referred to
+// in further requests.
+func (s *GameServer) NewGame(ctx context.Context, req *pb.NewGameRequest) (*pb.GameHistory, error) {
+	if len(req.Players) != 2 {
+		return nil, status.Error(codes.InvalidArgument, "exactly two players are required")
+	}
+	if req.Players[0].Nickname == req.Players[1].Nickname {
+		return nil, status.Error(codes.InvalidArgument, "two players with same nickname not supported")
+	}
+	if req.GoingFirst < -1 || req.GoingFirst > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid first player: %v", req.GoingFirst)
+	}
+	boardLayout, letterDistributionName := game.HistoryToVariant(&pb.GameHistory{
+		Lexicon: req.Lexicon, Variant: req.Variant})
+
+	rules, err := game.NewBasicGameRules(s.cfg, boardLayout, letterDistributionName)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	g, err := game.NewGame(rules, req.Players)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	g.SetNextFirst(int(req.GoingFirst))
+	g.StartGame()
+	// Challenges roll the game back, so we need to keep a backup.
+	g.SetBackupMode(game.InteractiveGameplayMode)
+	g.SetChallengeRule(req.ChallengeRule)
+	if req.Lexicon != "" {
+		g.History().Lexicon = req.Lexicon
+	}
+	g.History().Variant = req.Variant
+
+	lg := &liveGame{game: g, subscribers: make(map[chan struct{}]bool)}
+	s.Lock()
+	s.games[g.Uid()] = lg
+	s.Unlock()
+	log.Debug().Str("gameID", g.Uid()).Msg("created-game")
+
+	return proto.Clone(g.History()).(*pb.GameHistory), nil
+}
+
+// GetGameHistory returns the full history of the game so far.
+func (s *GameServer) GetGameHistory(ctx context.Context, req *pb.GameRequest) (*pb.GameHistory, error) {
+	lg, err := s.lookup(req.GameId)
+	if err != nil {
+		return nil, err
+	}
+	lg.Lock()
+	defer lg.Unlock()
+	return proto.Clone(lg.game.History()).(*pb.GameHistory), nil
+}
+
+// moveFromRequest converts a user-submitted event into a move for the
+// player on turn. Challenges are handled separately.
+func moveFromRequest(g *game.Game, evt *pb.GameEvent) (*move.Move, error) {
+	alph := g.Alphabet()
+	rack := g.RackFor(g.PlayerOnTurn())
+
+	switch evt.Type {
+	case pb.GameEvent_TILE_PLACEMENT_MOVE:
+		return g.CreateAndScorePlacementMove(evt.Position, evt.PlayedTiles, rack.String())
+
+	case pb.GameEvent_EXCHANGE:
+		tiles, err := alphabet.ToMachineWord(evt.Exchanged, alph)
+		if err != nil {
+			return nil, err
+		}
+		leave, err := game.Leave(rack.TilesOn(), tiles)
+		if err != nil {
+			return nil, err
+		}
+		return move.NewExchangeMove(tiles, leave, alph), nil
+
+	case pb.GameEvent_PASS:
+		return move.NewPassMove(rack.TilesOn(), alph), nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "event type %v cannot be submitted", evt.Type)
+}
+
+// SubmitMove plays the submitted move for the player on turn.
+func (s *GameServer) SubmitMove(ctx context.Context, req *pb.SubmitMoveRequest) (*pb.SubmitMoveResponse, error) {
+	if req.Event == nil {
+		return nil, status.Error(codes.InvalidArgument, "no event submitted")
+	}
+	lg, err := s.lookup(req.GameId)
+	if err != nil {
+		return nil, err
+	}
+	lg.Lock()
+	defer lg.Unlock()
+
+	g := lg.game
+	if g.Playing() == pb.PlayState_GAME_OVER {
+		return nil, status.Error(codes.FailedPrecondition, "game is over")
+	}
+	if req.Event.Nickname != g.NickOnTurn() {
+		return nil, status.Errorf(codes.FailedPrecondition, "it is not %v's turn",
+			req.Event.Nickname)
+	}
+	mover := g.PlayerOnTurn()
+	numEvents := len(g.History().Events)
+
+	if req.Event.Type == pb.GameEvent_CHALLENGE {
+		_, err = g.ChallengeEvent(0, int(req.MillisRemaining))
+	} else {
+		var m *move.Move
+		m, err = moveFromRequest(g, req.Event)
+		if err == nil {
+			err = g.PlayMove(m, true, int(req.MillisRemaining))
+		}
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &pb.SubmitMoveResponse{
+		PlayState: g.Playing(),
+		Rack:      g.RackLettersFor(mover),
+	}
+	for _, evt := range g.History().Events[numEvents:] {
+		resp.Events = append(resp.Events, proto.Clone(evt).(*pb.GameEvent))
+	}
+	lg.notify()
+	return resp, nil
+}
+
+// StreamEvents streams all of the game's events, including future ones,
+// until the game is over or the client goes away.
+func (s *GameServer) StreamEvents(req *pb.GameRequest, stream pb.GameService_StreamEventsServer) error {
+	lg, err := s.lookup(req.GameId)
+	if err != nil {
+		return err
+	}
+	notify := lg.subscribe()
+	defer lg.unsubscribe(notify)
+
+	sent := 0
+	for {
+		lg.Lock()
+		var evts []*pb.GameEvent
+		for _, evt := range lg.game.History().Events[sent:] {
+			evts = append(evts, proto.Clone(evt).(*pb.GameEvent))
+		}
+		over := lg.game.Playing() == pb.PlayState_GAME_OVER
+		lg.Unlock()
+
+		for _, evt := range evts {
+			if err := stream.Send(evt); err != nil {
+				return err
+			}
+			sent++
+		}
+		if over {
+			return nil
+		}
+		select {
+		case <-notify:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/domino14/cwgame/config"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func newTestGame(is *is.I, s *GameServer, rule pb.ChallengeRule) *pb.GameHistory {
+	hist, err := s.NewGame(context.Background(), &pb.NewGameRequest{
+		Players: []*pb.PlayerInfo{
+			{Nickname: "JD", RealName: "Jesse"},
+			{Nickname: "cesar", RealName: "César"},
+		},
+		Lexicon:       "NWL18",
+		ChallengeRule: rule,
+		GoingFirst:    0,
+	})
+	is.NoErr(err)
+	return hist
+}
+
+// firstPlay returns two non-blank tiles from the rack, to be played through
+// the center square.
+func firstPlay(rack string) string {
+	return strings.ReplaceAll(rack, "?", "")[:2]
+}
+
+func TestNewGameAndMoves(t *testing.T) {
+	is := is.New(t)
+	s := NewGameServer(&DefaultConfig)
+	ctx := context.Background()
+
+	hist := newTestGame(is, s, pb.ChallengeRule_SINGLE)
+	is.Equal(hist.Lexicon, "NWL18")
+	is.Equal(len(hist.Events), 0)
+
+	tiles := firstPlay(hist.LastKnownRacks[0])
+	resp, err := s.SubmitMove(ctx, &pb.SubmitMoveRequest{
+		GameId: hist.Uid,
+		Event: &pb.GameEvent{Nickname: "JD", Type: pb.GameEvent_TILE_PLACEMENT_MOVE,
+			Position: "8H", PlayedTiles: tiles},
+		MillisRemaining: 1500,
+	})
+	is.NoErr(err)
+	is.Equal(len(resp.Events), 1)
+	is.Equal(resp.Events[0].PlayedTiles, tiles)
+	is.Equal(resp.Events[0].GetMillisRemaining(), int32(1500))
+	is.Equal(len(resp.Rack), 7)
+
+	// It's not JD's turn anymore.
+	_, err = s.SubmitMove(ctx, &pb.SubmitMoveRequest{
+		GameId: hist.Uid,
+		Event:  &pb.GameEvent{Nickname: "JD", Type: pb.GameEvent_PASS},
+	})
+	is.Equal(status.Code(err), codes.FailedPrecondition)
+
+	// cesar challenges; every word is valid so JD gets a 0-point bonus.
+	resp, err = s.SubmitMove(ctx, &pb.SubmitMoveRequest{
+		GameId: hist.Uid,
+		Event:  &pb.GameEvent{Nickname: "cesar", Type: pb.GameEvent_CHALLENGE},
+	})
+	is.NoErr(err)
+	is.Equal(len(resp.Events), 1)
+	is.Equal(resp.Events[0].Type, pb.GameEvent_CHALLENGE_BONUS)
+
+	hist, err = s.GetGameHistory(ctx, &pb.GameRequest{GameId: hist.Uid})
+	is.NoErr(err)
+	rack := hist.LastKnownRacks[1]
+	resp, err = s.SubmitMove(ctx, &pb.SubmitMoveRequest{
+		GameId: hist.Uid,
+		Event: &pb.GameEvent{Nickname: "cesar", Type: pb.GameEvent_EXCHANGE,
+			Exchanged: rack[:3]},
+	})
+	is.NoErr(err)
+	is.Equal(resp.Events[0].Type, pb.GameEvent_EXCHANGE)
+	is.Equal(sortedTiles(resp.Events[0].Rack), sortedTiles(rack))
+
+	// Exchanging tiles you don't have is an error.
+	_, err = s.SubmitMove(ctx, &pb.SubmitMoveRequest{
+		GameId: hist.Uid,
+		Event: &pb.GameEvent{Nickname: "JD", Type: pb.GameEvent_EXCHANGE,
+			Exchanged: "ZZZ"},
+	})
+	is.Equal(status.Code(err), codes.InvalidArgument)
+
+	hist, err = s.GetGameHistory(ctx, &pb.GameRequest{GameId: hist.Uid})
+	is.NoErr(err)
+	is.Equal(len(hist.Events), 3)
+
+	_, err = s.GetGameHistory(ctx, &pb.GameRequest{GameId: "nonexistent"})
+	is.Equal(status.Code(err), codes.NotFound)
+}
+
+func TestStreamEvents(t *testing.T) {
+	is := is.New(t)
+	s := NewGameServer(&DefaultConfig)
+	ctx := context.Background()
+
+	lis := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	pb.RegisterGameServiceServer(gs, s)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	is.NoErr(err)
+	defer conn.Close()
+	client := pb.NewGameServiceClient(conn)
+
+	hist, err := client.NewGame(ctx, &pb.NewGameRequest{
+		Players: []*pb.PlayerInfo{
+			{Nickname: "JD", RealName: "Jesse"},
+			{Nickname: "cesar", RealName: "César"},
+		},
+		GoingFirst: 1,
+	})
+	is.NoErr(err)
+
+	_, err = client.SubmitMove(ctx, &pb.SubmitMoveRequest{
+		GameId: hist.Uid,
+		Event:  &pb.GameEvent{Nickname: "cesar", Type: pb.GameEvent_PASS},
+	})
+	is.NoErr(err)
+
+	stream, err := client.StreamEvents(ctx, &pb.GameRequest{GameId: hist.Uid})
+	is.NoErr(err)
+	// The existing event is sent right away.
+	evt, err := stream.Recv()
+	is.NoErr(err)
+	is.Equal(evt.Nickname, "cesar")
+	is.Equal(evt.Type, pb.GameEvent_PASS)
+
+	// Passing until the game ends should stream every pass, followed by
+	// the end-of-game rack penalties, and then close the stream.
+	nicks := []string{"JD", "cesar", "JD", "cesar", "JD"}
+	for _, nick := range nicks {
+		_, err = client.SubmitMove(ctx, &pb.SubmitMoveRequest{
+			GameId: hist.Uid,
+			Event:  &pb.GameEvent{Nickname: nick, Type: pb.GameEvent_PASS},
+		})
+		is.NoErr(err)
+	}
+	var types []pb.GameEvent_Type
+	for {
+		evt, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		is.NoErr(err)
+		types = append(types, evt.Type)
+	}
+	is.Equal(types, []pb.GameEvent_Type{
+		pb.GameEvent_PASS, pb.GameEvent_PASS, pb.GameEvent_PASS,
+		pb.GameEvent_PASS, pb.GameEvent_PASS,
+		pb.GameEvent_END_RACK_PENALTY, pb.GameEvent_END_RACK_PENALTY,
+	})
+}
+
+// sortedTiles puts the tiles of a rack in order, as events and racks
+// do not necessarily list them in the same order.
+func sortedTiles(rack string) string {
+	tiles := strings.Split(rack, "")
+	sort.Strings(tiles)
+	return strings.Join(tiles, "")
+}
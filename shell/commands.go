@@ -0,0 +1,541 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/move"
+)
+
+var reCoords = regexp.MustCompile(`^([A-Za-z][0-9]+|[0-9]+[A-Za-z])$`)
+
+func isCoords(s string) bool {
+	return reCoords.MatchString(s)
+}
+
+var errNoGame = errors.New("there is no game; start one with new or load")
+
+// makeRules creates the rules for a game with the given history, using
+// the shell's lexicon if one was set.
+func (s *Shell) makeRules(h *pb.GameHistory) (*game.GameRules, error) {
+	boardLayout, letterDistributionName := game.HistoryToVariant(h)
+	rules, err := game.NewBasicGameRules(s.cfg, boardLayout, letterDistributionName)
+	if err != nil {
+		return nil, err
+	}
+	if s.lexiconFile == "" {
+		return rules, nil
+	}
+	lex, err := lexicon.LoadWordListFile(s.lexiconFile, rules.LetterDistribution().Alphabet())
+	if err != nil {
+		return nil, err
+	}
+	return game.NewGameRules(s.cfg, rules.LetterDistribution(), rules.Board(), lex,
+		rules.CrossSetGen()), nil
+}
+
+// setHistory replaces the game with one created from the history, at the
+// given turn.
+func (s *Shell) setHistory(h *pb.GameHistory, turn int) error {
+	rules, err := s.makeRules(h)
+	if err != nil {
+		return err
+	}
+	g, err := game.ReplayHistory(h, rules, turn)
+	if err != nil {
+		return err
+	}
+	if h.ChallengeRule == pb.ChallengeRule_VOID {
+		g.SetChallengeRule(s.rule)
+	}
+	g.SetBackupMode(game.InteractiveGameplayMode)
+	s.game = g
+	s.rules = rules
+	s.viewing = -1
+	s.unplayable = false
+	if turn < len(h.Events) {
+		s.viewing = turn
+	}
+	return nil
+}
+
+func (s *Shell) newGame(args []string) error {
+	nicks := []string{"player1", "player2"}
+	if len(args) != 0 && len(args) != 2 {
+		return errors.New("need the nicknames of both players")
+	}
+	if len(args) == 2 {
+		if args[0] == args[1] {
+			return errors.New("the players need different nicknames")
+		}
+		nicks = args
+	}
+	players := []*pb.PlayerInfo{
+		{Nickname: nicks[0], RealName: nicks[0]},
+		{Nickname: nicks[1], RealName: nicks[1]},
+	}
+	rules, err := s.makeRules(&pb.GameHistory{Lexicon: s.cfg.DefaultLexicon})
+	if err != nil {
+		return err
+	}
+	g, err := game.NewGame(rules, players)
+	if err != nil {
+		return err
+	}
+	g.SetNextFirst(0)
+	g.StartGame()
+	g.SetBackupMode(game.InteractiveGameplayMode)
+	g.SetChallengeRule(s.rule)
+	if s.lexiconFile == "" {
+		g.History().Lexicon = s.cfg.DefaultLexicon
+	}
+	s.game = g
+	s.rules = rules
+	s.viewing = -1
+	s.undoStack = nil
+	s.unplayable = false
+	s.printf("new game: %v vs %v\n", nicks[0], nicks[1])
+	s.printRack()
+	return nil
+}
+
+func (s *Shell) load(args []string) error {
+	if len(args) != 1 {
+		return errors.New("need a file to load")
+	}
+	h, err := gcgio.ParseGCG(s.cfg, args[0])
+	if err != nil {
+		return err
+	}
+	err = s.setHistory(h, len(h.Events))
+	if err != nil {
+		return err
+	}
+	s.undoStack = nil
+	s.printf("loaded %v: %d events\n", args[0], len(h.Events))
+	return nil
+}
+
+func (s *Shell) save(args []string) error {
+	if s.game == nil {
+		return errNoGame
+	}
+	if len(args) != 1 {
+		return errors.New("need a file to save to")
+	}
+	gcg, err := gcgio.GameHistoryToGCG(s.game.History(), true)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(args[0], []byte(gcg), 0644)
+	if err != nil {
+		return err
+	}
+	s.printf("saved to %v\n", args[0])
+	return nil
+}
+
+func (s *Shell) setLexicon(args []string) error {
+	if len(args) != 1 {
+		return errors.New("need a word list file")
+	}
+	old := s.lexiconFile
+	s.lexiconFile = args[0]
+	if s.game == nil {
+		// Check that it loads, at least with the default distribution.
+		_, err := s.makeRules(&pb.GameHistory{})
+		if err != nil {
+			s.lexiconFile = old
+		}
+		return err
+	}
+	s.syncRacks()
+	err := s.setHistory(s.game.History(), s.currentTurn())
+	if err != nil {
+		s.lexiconFile = old
+	}
+	return err
+}
+
+func (s *Shell) setRule(args []string) error {
+	if len(args) != 1 {
+		return errors.New("need a challenge rule")
+	}
+	rule, ok := pb.ChallengeRule_value[strings.ToUpper(args[0])]
+	if !ok {
+		return fmt.Errorf("unknown challenge rule %v", args[0])
+	}
+	s.rule = pb.ChallengeRule(rule)
+	if s.game != nil {
+		s.game.SetChallengeRule(s.rule)
+	}
+	return nil
+}
+
+func (s *Shell) setRack(args []string) error {
+	if s.game == nil {
+		return errNoGame
+	}
+	if len(args) != 1 {
+		return errors.New("need the tiles on the rack")
+	}
+	g := s.game
+	rack := alphabet.RackFromString(strings.ToUpper(args[0]), g.Alphabet())
+	if rack.NumTiles() > game.RackTileLimit {
+		return fmt.Errorf("a rack can't have more than %d tiles", game.RackTileLimit)
+	}
+	// Keep the opponent's rack if both racks fit.
+	onturn := g.PlayerOnTurn()
+	racks := make([]*alphabet.Rack, 2)
+	racks[onturn] = rack
+	racks[1-onturn] = alphabet.RackFromString(g.RackLettersFor(1-onturn), g.Alphabet())
+	if g.SetRacksForBoth(racks) != nil {
+		err := g.SetRackFor(onturn, rack)
+		if err != nil {
+			return err
+		}
+	}
+	s.unplayable = false
+	s.syncRacks()
+	s.printRack()
+	return nil
+}
+
+// syncRacks records the current racks in the history, unless an earlier
+// turn is being looked at.
+func (s *Shell) syncRacks() {
+	if s.viewing != -1 {
+		return
+	}
+	g := s.game
+	g.History().LastKnownRacks = []string{g.RackLettersFor(0), g.RackLettersFor(1)}
+}
+
+// currentTurn is the turn that the game is at.
+func (s *Shell) currentTurn() int {
+	if s.viewing != -1 {
+		return s.viewing
+	}
+	return len(s.game.History().Events)
+}
+
+// beginMove makes sure a move can be made, and saves the history so that
+// the move can be undone. If an earlier turn is being looked at, all the
+// events after it are removed, so that the game continues from there.
+func (s *Shell) beginMove() error {
+	if s.game == nil {
+		return errNoGame
+	}
+	g := s.game
+	h := g.History()
+	s.syncRacks()
+	s.undoStack = append(s.undoStack, proto.Clone(h).(*pb.GameHistory))
+	s.unplayable = s.viewing == -1
+	if s.viewing != -1 {
+		h.Events = h.Events[:s.viewing]
+		h.FinalScores = nil
+		h.Winner = 0
+		h.LastKnownRacks = []string{g.RackLettersFor(0), g.RackLettersFor(1)}
+		s.viewing = -1
+	}
+	return nil
+}
+
+// endMove reports the events that the move added, or takes back the
+// undo state if the move failed.
+func (s *Shell) endMove(nevents int, err error) error {
+	if err != nil {
+		s.undoStack = s.undoStack[:len(s.undoStack)-1]
+		s.unplayable = false
+		return err
+	}
+	s.syncRacks()
+	for _, evt := range s.game.History().Events[nevents:] {
+		s.printf("%v (%d)\n", describe(evt), evt.Cumulative)
+	}
+	if s.game.Playing() == pb.PlayState_GAME_OVER {
+		s.printf("game over: %v\n", s.scores())
+	} else {
+		s.printRack()
+	}
+	return nil
+}
+
+func (s *Shell) play(args []string) error {
+	if len(args) != 2 {
+		return errors.New("a play needs coordinates and a word, e.g. 8D QUIXOTIC")
+	}
+	if err := s.beginMove(); err != nil {
+		return err
+	}
+	nevents := len(s.game.History().Events)
+	_, err := s.game.PlayScoringMove(strings.ToUpper(args[0]), args[1], true)
+	return s.endMove(nevents, err)
+}
+
+func (s *Shell) exchange(args []string) error {
+	if len(args) != 1 {
+		return errors.New("need the tiles to exchange")
+	}
+	if err := s.beginMove(); err != nil {
+		return err
+	}
+	g := s.game
+	nevents := len(g.History().Events)
+	m, err := s.exchangeMove(strings.ToUpper(args[0]))
+	if err == nil {
+		err = g.PlayMove(m, true, 0)
+	}
+	return s.endMove(nevents, err)
+}
+
+func (s *Shell) exchangeMove(tiles string) (*move.Move, error) {
+	g := s.game
+	mw, err := alphabet.ToMachineWord(tiles, g.Alphabet())
+	if err != nil {
+		return nil, err
+	}
+	leave, err := game.Leave(g.RackFor(g.PlayerOnTurn()).TilesOn(), mw)
+	if err != nil {
+		return nil, err
+	}
+	return move.NewExchangeMove(mw, leave, g.Alphabet()), nil
+}
+
+func (s *Shell) pass(args []string) error {
+	if err := s.beginMove(); err != nil {
+		return err
+	}
+	g := s.game
+	nevents := len(g.History().Events)
+	m := move.NewPassMove(g.RackFor(g.PlayerOnTurn()).TilesOn(), g.Alphabet())
+	return s.endMove(nevents, g.PlayMove(m, true, 0))
+}
+
+func (s *Shell) challenge(args []string) error {
+	bonus := 0
+	if len(args) > 1 {
+		return errors.New("challenge takes at most one argument")
+	}
+	if len(args) == 1 {
+		var err error
+		bonus, err = strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("bad challenge bonus %v", args[0])
+		}
+	}
+	if err := s.beginMove(); err != nil {
+		return err
+	}
+	// A challenge can take back a play itself, so it can't be unplayed.
+	s.unplayable = false
+	nevents := len(s.game.History().Events)
+	err := s.replayLastPlay()
+	if err == nil {
+		var legal bool
+		legal, err = s.game.ChallengeEvent(bonus, 0)
+		if err == nil && legal {
+			s.printf("the play is valid\n")
+		} else if err == nil {
+			s.printf("the play is not valid\n")
+		}
+	}
+	return s.endMove(nevents, err)
+}
+
+// replayLastPlay takes back the last play and makes it again. The game
+// only remembers the state before the last play if the play was just
+// made, and not if the game was loaded, looked at from another turn or
+// had racks changed since. Making the play again here means that a
+// challenge can always take it back correctly.
+func (s *Shell) replayLastPlay() error {
+	g := s.game
+	h := g.History()
+	n := len(h.Events)
+	if n == 0 {
+		return errors.New("there is nothing to challenge")
+	}
+	last := h.Events[n-1]
+	if last.Type != pb.GameEvent_TILE_PLACEMENT_MOVE {
+		return errors.New("the last event was not a play")
+	}
+	challengee := 0
+	if h.Players[1].Nickname == last.Nickname {
+		challengee = 1
+	}
+	alph := g.Alphabet()
+	racks := []string{g.RackLettersFor(0), g.RackLettersFor(1)}
+	rackSet := func(racks []string) []*alphabet.Rack {
+		// The game takes ownership of these, so they must be new each time.
+		return []*alphabet.Rack{
+			alphabet.RackFromString(racks[0], alph),
+			alphabet.RackFromString(racks[1], alph),
+		}
+	}
+	err := g.PlayToTurn(n - 1)
+	if err != nil {
+		return err
+	}
+	h.Events = h.Events[:n-1]
+	before := []string{racks[0], racks[1]}
+	before[challengee] = last.Rack
+	err = g.SetRacksForBoth(rackSet(before))
+	if err != nil {
+		return err
+	}
+	m, err := g.CreateAndScorePlacementMove(last.Position, last.PlayedTiles, last.Rack)
+	if err != nil {
+		return err
+	}
+	err = g.PlayMove(m, true, int(last.GetMillisRemaining()))
+	if err != nil {
+		return err
+	}
+	h.Events[n-1].Note = last.Note
+	h.Events[n-1].Annotation = last.Annotation
+	return g.SetRacksForBoth(rackSet(racks))
+}
+
+func (s *Shell) turn(args []string) error {
+	if s.game == nil {
+		return errNoGame
+	}
+	h := s.game.History()
+	t := len(h.Events)
+	if len(args) == 1 {
+		var err error
+		t, err = strconv.Atoi(args[0])
+		if err != nil || t < 0 || t > len(h.Events) {
+			return fmt.Errorf("turn must be a number from 0 to %d", len(h.Events))
+		}
+	}
+	if s.viewing == -1 && t == len(h.Events) {
+		return s.show(nil)
+	}
+	// Keep the latest racks, as going through the history loses them.
+	s.syncRacks()
+	s.unplayable = false
+	err := s.game.PlayToTurn(t)
+	if err != nil {
+		return err
+	}
+	s.viewing = t
+	if t == len(h.Events) {
+		s.viewing = -1
+	}
+	return s.show(nil)
+}
+
+func (s *Shell) undo(args []string) error {
+	if s.game == nil {
+		return errNoGame
+	}
+	if len(s.undoStack) == 0 {
+		return errors.New("there is nothing to undo; use turn to go back to an earlier position")
+	}
+	h := s.undoStack[len(s.undoStack)-1]
+	s.undoStack = s.undoStack[:len(s.undoStack)-1]
+	// The challenge rule may have been changed since.
+	h.ChallengeRule = s.game.History().ChallengeRule
+	if s.unplayable {
+		s.unplay(h)
+	} else {
+		// Only the last move can be unplayed, so anything else goes back
+		// through the history.
+		err := s.setHistory(h, len(h.Events))
+		if err != nil {
+			return err
+		}
+	}
+	if len(h.Events) > 0 {
+		s.printf("back to after: %v\n", describe(h.Events[len(h.Events)-1]))
+	} else {
+		s.printf("back to the start of the game\n")
+	}
+	s.printRack()
+	return nil
+}
+
+// unplay takes back the last move, which was made from the latest turn
+// of h, with the game's backup of the state before it.
+func (s *Shell) unplay(h *pb.GameHistory) {
+	g := s.game
+	onturn := 0
+	if g.History().Events[len(h.Events)].Nickname == h.Players[1].Nickname {
+		onturn = 1
+	}
+	g.UnplayLastMove()
+	g.SetPlayerOnTurn(onturn)
+	g.SetHistory(h)
+	g.SetTurn(len(h.Events))
+	s.unplayable = false
+}
+
+func (s *Shell) note(args []string) error {
+	if s.game == nil {
+		return errNoGame
+	}
+	t := s.currentTurn()
+	if t == 0 {
+		return errors.New("there is no event to add a note to")
+	}
+	evt := s.game.History().Events[t-1]
+	text := strings.Join(args, " ")
+	if text == "" {
+		s.printf("%v\n", evt.Note)
+		return nil
+	}
+	if evt.Note != "" {
+		evt.Note += "\n"
+	}
+	evt.Note += text
+	return nil
+}
+
+func (s *Shell) show(args []string) error {
+	if s.game == nil {
+		return errNoGame
+	}
+	s.printf("%v\n", s.game.ToDisplayText())
+	return nil
+}
+
+func (s *Shell) printRack() {
+	g := s.game
+	if g.Playing() == pb.PlayState_GAME_OVER {
+		return
+	}
+	s.printf("%v to play with %v\n", g.NickOnTurn(), g.RackLettersFor(g.PlayerOnTurn()))
+}
+
+func (s *Shell) scores() string {
+	g := s.game
+	players := g.History().Players
+	return fmt.Sprintf("%v %d, %v %d", players[0].Nickname, g.PointsFor(0),
+		players[1].Nickname, g.PointsFor(1))
+}
+
+// describe is like game.Summary, but always says who the event is for.
+func describe(evt *pb.GameEvent) string {
+	switch evt.Type {
+	case pb.GameEvent_CHALLENGE_BONUS:
+		return fmt.Sprintf("%s gets a challenge bonus of %d", evt.Nickname, evt.Bonus)
+	case pb.GameEvent_END_RACK_PTS:
+		return fmt.Sprintf("%s gets %d from the opponent's rack of %s", evt.Nickname,
+			evt.EndRackPoints, evt.Rack)
+	case pb.GameEvent_PHONY_TILES_RETURNED:
+		return fmt.Sprintf("%s's play %s was challenged off", evt.Nickname, evt.PlayedTiles)
+	}
+	return game.Summary(evt)
+}
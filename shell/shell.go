@@ -0,0 +1,151 @@
+// Package shell implements an interactive command interpreter for playing
+// and annotating games. Commands are read one per line, so the shell can
+// also be driven by piping a script into it.
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+// errQuit is returned by the quit command to end the session.
+var errQuit = errors.New("quit")
+
+type command struct {
+	name string
+	args string
+	help string
+	run  func(s *Shell, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"new", "[nick1 nick2]", "start a new game; nick1 goes first", (*Shell).newGame},
+		{"load", "<file.gcg>", "load a game and go to its last turn", (*Shell).load},
+		{"save", "<file.gcg>", "save the game", (*Shell).save},
+		{"lexicon", "<file>", "use a word list (one word per line) to judge words", (*Shell).setLexicon},
+		{"rule", "<challenge rule>", "set the challenge rule (void, single, double, five_point, ten_point)", (*Shell).setRule},
+		{"rack", "<tiles>", "set the rack of the player on turn", (*Shell).setRack},
+		{"exchange", "<tiles>", "exchange tiles", (*Shell).exchange},
+		{"pass", "", "pass", (*Shell).pass},
+		{"challenge", "[bonus]", "challenge the last play", (*Shell).challenge},
+		{"turn", "[n]", "show the position after n events; the latest one if n is not given", (*Shell).turn},
+		{"undo", "", "take back the last move or challenge", (*Shell).undo},
+		{"note", "[text]", "add a note to the event just played, or show its note", (*Shell).note},
+		{"show", "", "show the board", (*Shell).show},
+		{"help", "", "show this help", (*Shell).help},
+		{"quit", "", "leave the shell", (*Shell).quit},
+	}
+}
+
+// A Shell reads commands from an input and writes its responses to an
+// output.
+type Shell struct {
+	// Prompt is written out before reading each command.
+	Prompt string
+	// StopOnError makes Run return at the first command that fails,
+	// which is usually what is wanted when running a script.
+	StopOnError bool
+
+	cfg         *config.Config
+	in          *bufio.Scanner
+	out         io.Writer
+	rule        pb.ChallengeRule
+	lexiconFile string
+
+	game  *game.Game
+	rules *game.GameRules
+	// viewing is the turn being looked at, or -1 if it is the latest one.
+	viewing int
+	// undoStack has a copy of the history before every move.
+	undoStack []*pb.GameHistory
+	// unplayable is true if nothing has changed the game since a play,
+	// exchange or pass at the latest turn, so that the game's backup of
+	// the state before it is still good.
+	unplayable bool
+}
+
+// New creates a shell that reads commands from in and writes to out.
+func New(cfg *config.Config, in io.Reader, out io.Writer) *Shell {
+	return &Shell{
+		cfg:     cfg,
+		in:      bufio.NewScanner(in),
+		out:     out,
+		rule:    pb.ChallengeRule_DOUBLE,
+		viewing: -1,
+	}
+}
+
+// Game returns the game being played, or nil if there is none.
+func (s *Shell) Game() *game.Game {
+	return s.game
+}
+
+func (s *Shell) printf(format string, a ...interface{}) {
+	fmt.Fprintf(s.out, format, a...)
+}
+
+// Run reads and executes commands until the input ends or the quit
+// command is given.
+func (s *Shell) Run() error {
+	for {
+		s.printf("%s", s.Prompt)
+		if !s.in.Scan() {
+			return s.in.Err()
+		}
+		err := s.Execute(s.in.Text())
+		if err == errQuit {
+			return nil
+		}
+		if err != nil {
+			s.printf("error: %v\n", err)
+			if s.StopOnError {
+				return err
+			}
+		}
+	}
+}
+
+// Execute runs a single command line. Blank lines and lines starting
+// with # are ignored.
+func (s *Shell) Execute(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	fields := strings.Fields(line)
+	log.Debug().Strs("fields", fields).Msg("execute")
+	name := strings.ToLower(fields[0])
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(s, fields[1:])
+		}
+	}
+	if isCoords(fields[0]) {
+		return s.play(fields)
+	}
+	return fmt.Errorf("unknown command %v; type help for a list of commands", fields[0])
+}
+
+func (s *Shell) help(args []string) error {
+	s.printf("%-28v %v\n", "<coords> <word>", "make a play, e.g. 8D QUIXOTIC or H7 c.EW")
+	for _, c := range commands {
+		s.printf("%-28v %v\n", strings.TrimSpace(c.name+" "+c.args), c.help)
+	}
+	return nil
+}
+
+func (s *Shell) quit(args []string) error {
+	return errQuit
+}
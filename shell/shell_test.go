@@ -0,0 +1,137 @@
+package shell
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func runScript(t *testing.T, script string) (*Shell, string) {
+	var out bytes.Buffer
+	s := New(&DefaultConfig, strings.NewReader(script), &out)
+	s.StopOnError = true
+	err := s.Run()
+	if err != nil {
+		t.Fatalf("script failed: %v\n%v", err, out.String())
+	}
+	return s, out.String()
+}
+
+func TestPlayAndUndo(t *testing.T) {
+	is := is.New(t)
+	s, out := runScript(t, `
+new alice bob
+rack DINNVWY
+8d WINDY
+rack ADEEGIL
+# a comment
+exchange AE
+pass
+undo
+`)
+	h := s.Game().History()
+	is.Equal(len(h.Events), 2)
+	is.Equal(h.Events[0].Score, int32(32))
+	is.Equal(h.Events[1].Type, pb.GameEvent_EXCHANGE)
+	is.Equal(h.Events[1].Exchanged, "AE")
+	is.Equal(s.Game().NickOnTurn(), "alice")
+	is.True(strings.Contains(out, "alice played 8D WINDY for 32 pts from a rack of DINNVWY (32)"))
+
+	// The pass was unplayed, which leaves the game as it would be after
+	// replaying the history.
+	g, err := game.ReplayHistory(h, s.rules, len(h.Events))
+	is.NoErr(err)
+	is.Equal(s.Game().ToDisplayText(), g.ToDisplayText())
+	is.Equal(s.Game().Turn(), 2)
+
+	// Only the last move can be unplayed; the one before it is replayed.
+	is.NoErr(s.Execute("undo"))
+	is.Equal(len(h.Events), 2)
+	h = s.Game().History()
+	is.Equal(len(h.Events), 1)
+	is.Equal(s.Game().NickOnTurn(), "bob")
+	is.Equal(s.Game().PointsFor(0), 32)
+}
+
+func TestChallenge(t *testing.T) {
+	is := is.New(t)
+	dir, err := ioutil.TempDir("", "shell")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+	wordList := filepath.Join(dir, "tiny.txt")
+	is.NoErr(ioutil.WriteFile(wordList, []byte("WINDY\n"), 0644))
+
+	s, out := runScript(t, `
+lexicon `+wordList+`
+rule five_point
+new alice bob
+rack DINNVWY
+8D WINDY
+challenge
+rack ADEEGIL
+7C GALE
+challenge
+`)
+	h := s.Game().History()
+	is.Equal(h.ChallengeRule, pb.ChallengeRule_FIVE_POINT)
+	is.Equal(len(h.Events), 4)
+	is.Equal(h.Events[1].Type, pb.GameEvent_CHALLENGE_BONUS)
+	is.Equal(h.Events[1].Cumulative, int32(37))
+	is.Equal(h.Events[3].Type, pb.GameEvent_PHONY_TILES_RETURNED)
+	is.Equal(h.Events[3].Cumulative, int32(0))
+	is.Equal(s.Game().RackLettersFor(1), "ADEEGIL")
+	is.Equal(s.Game().NickOnTurn(), "alice")
+	is.True(strings.Contains(out, "bob's play GALE was challenged off (0)"))
+}
+
+func TestAnnotateLoadedGame(t *testing.T) {
+	is := is.New(t)
+	dir, err := ioutil.TempDir("", "shell")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+	saved := filepath.Join(dir, "annotated.gcg")
+
+	s, _ := runScript(t, `
+load ../gcgio/testdata/doug_v_emely.gcg
+turn 2
+note   should have played JAVA
+turn 5
+note a good bingo
+turn
+save `+saved+`
+load `+saved+`
+`)
+	h := s.Game().History()
+	is.Equal(len(h.Events), 28)
+	is.Equal(h.Events[1].Note, "should have played JAVA")
+	is.Equal(h.Events[4].Note, "a good bingo")
+
+	// Playing from an earlier turn continues the game from there.
+	is.NoErr(s.Execute("turn 4"))
+	is.NoErr(s.Execute("rack ADENOST"))
+	is.NoErr(s.Execute("10B DONATES"))
+	is.Equal(len(h.Events), 5)
+	is.Equal(h.Events[4].Score, int32(82))
+	is.NoErr(s.Execute("undo"))
+	is.Equal(len(s.Game().History().Events), 28)
+}
+
+func TestUnknownCommand(t *testing.T) {
+	is := is.New(t)
+	var out bytes.Buffer
+	s := New(&DefaultConfig, strings.NewReader("frobnicate\npass\nquit\nshow\n"), &out)
+	is.NoErr(s.Run())
+	is.Equal(out.String(), "error: unknown command frobnicate; type help for a list of commands\n"+
+		"error: there is no game; start one with new or load\n")
+}
@@ -0,0 +1,189 @@
+// Package stats works out statistics for each player of a game from its
+// history.
+package stats
+
+import (
+	"unicode"
+
+	"github.com/domino14/cwgame/alphabet"
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+)
+
+// A Play is a tile placement move.
+type Play struct {
+	Position string `json:"position"`
+	Tiles    string `json:"tiles"`
+	Score    int    `json:"score"`
+}
+
+// BonusSquares counts the bonus squares covered by a player's tiles.
+type BonusSquares struct {
+	TripleWord   int `json:"tripleWord"`
+	DoubleWord   int `json:"doubleWord"`
+	TripleLetter int `json:"tripleLetter"`
+	DoubleLetter int `json:"doubleLetter"`
+}
+
+// PlayerStats are the statistics of one player. Plays that were
+// challenged off only count towards PhoniesPlayed.
+type PlayerStats struct {
+	Nickname string `json:"nickname"`
+	Score    int    `json:"score"`
+	// Turns are the plays, exchanges and passes the player made,
+	// including turns lost to unsuccessful challenges.
+	Turns           int     `json:"turns"`
+	Plays           int     `json:"plays"`
+	AveragePerTurn  float64 `json:"averagePerTurn"`
+	Bingos          int     `json:"bingos"`
+	TilesPlayed     int     `json:"tilesPlayed"`
+	BlanksPlayed    int     `json:"blanksPlayed"`
+	SsPlayed        int     `json:"sPlayed"`
+	Exchanges       int     `json:"exchanges"`
+	Passes          int     `json:"passes"`
+	ChallengesWon   int     `json:"challengesWon"`
+	ChallengesLost  int     `json:"challengesLost"`
+	ChallengeBonus  int     `json:"challengeBonus"`
+	PhoniesPlayed   int     `json:"phoniesPlayed"`
+	PhoniesStanding int     `json:"phoniesStanding"`
+	// HighestPlay is nil if the player made no plays.
+	HighestPlay  *Play        `json:"highestPlay,omitempty"`
+	BonusSquares BonusSquares `json:"bonusSquares"`
+}
+
+// A Report has the statistics of every player, in the order of the
+// players in the history.
+type Report struct {
+	Lexicon string         `json:"lexicon"`
+	Players []*PlayerStats `json:"players"`
+}
+
+// Compute works out the statistics of a game. The history is replayed
+// with the rules to find the words formed and the squares covered by
+// each play. Phonies that were not challenged off can only be found if
+// the rules have a lexicon that knows which words are valid.
+func Compute(h *pb.GameHistory, rules *game.GameRules) (*Report, error) {
+	g, err := game.ReplayHistory(h, rules, len(h.Events))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Lexicon: h.Lexicon}
+	byNick := map[string]*PlayerStats{}
+	for i, p := range h.Players {
+		ps := &PlayerStats{Nickname: p.Nickname, Score: g.PointsFor(i)}
+		report.Players = append(report.Players, ps)
+		byNick[p.Nickname] = ps
+	}
+	opponent := func(nick string) *PlayerStats {
+		for _, ps := range report.Players {
+			if ps.Nickname != nick {
+				return ps
+			}
+		}
+		return nil
+	}
+
+	lex := rules.Lexicon()
+	_, acceptsAll := lex.(lexicon.AcceptAll)
+
+	for i, evt := range h.Events {
+		ps, ok := byNick[evt.Nickname]
+		if !ok {
+			continue
+		}
+		switch evt.Type {
+		case pb.GameEvent_TILE_PLACEMENT_MOVE:
+			ps.Turns++
+			withdrawn := i+1 < len(h.Events) &&
+				h.Events[i+1].Type == pb.GameEvent_PHONY_TILES_RETURNED
+			if withdrawn {
+				ps.PhoniesPlayed++
+				break
+			}
+			if !acceptsAll && hasPhony(evt.WordsFormed, lex) {
+				ps.PhoniesPlayed++
+				ps.PhoniesStanding++
+			}
+			addPlay(ps, evt, g.Board())
+
+		case pb.GameEvent_EXCHANGE:
+			ps.Turns++
+			ps.Exchanges++
+		case pb.GameEvent_PASS:
+			ps.Turns++
+			ps.Passes++
+		case pb.GameEvent_UNSUCCESSFUL_CHALLENGE_TURN_LOSS:
+			ps.Turns++
+			ps.ChallengesLost++
+		case pb.GameEvent_PHONY_TILES_RETURNED:
+			if opp := opponent(evt.Nickname); opp != nil {
+				opp.ChallengesWon++
+			}
+		case pb.GameEvent_CHALLENGE_BONUS:
+			ps.ChallengeBonus += int(evt.Bonus)
+			if opp := opponent(evt.Nickname); opp != nil {
+				opp.ChallengesLost++
+			}
+		}
+	}
+
+	for _, ps := range report.Players {
+		if ps.Turns > 0 {
+			ps.AveragePerTurn = float64(ps.Score) / float64(ps.Turns)
+		}
+	}
+	return report, nil
+}
+
+// addPlay adds a play that stayed on the board.
+func addPlay(ps *PlayerStats, evt *pb.GameEvent, b *board.GameBoard) {
+	ps.Plays++
+	if evt.IsBingo {
+		ps.Bingos++
+	}
+	if ps.HighestPlay == nil || int(evt.Score) > ps.HighestPlay.Score {
+		ps.HighestPlay = &Play{Position: evt.Position, Tiles: evt.PlayedTiles,
+			Score: int(evt.Score)}
+	}
+
+	row, col := int(evt.Row), int(evt.Column)
+	for _, t := range evt.PlayedTiles {
+		if t != alphabet.ASCIIPlayedThrough {
+			ps.TilesPlayed++
+			switch {
+			case unicode.IsLower(t):
+				ps.BlanksPlayed++
+			case t == 'S':
+				ps.SsPlayed++
+			}
+			switch b.GetBonus(row, col) {
+			case board.Bonus3WS:
+				ps.BonusSquares.TripleWord++
+			case board.Bonus2WS:
+				ps.BonusSquares.DoubleWord++
+			case board.Bonus3LS:
+				ps.BonusSquares.TripleLetter++
+			case board.Bonus2LS:
+				ps.BonusSquares.DoubleLetter++
+			}
+		}
+		if evt.Direction == pb.GameEvent_VERTICAL {
+			row++
+		} else {
+			col++
+		}
+	}
+}
+
+func hasPhony(words []string, lex lexicon.Lexicon) bool {
+	for _, w := range words {
+		mw, err := alphabet.ToMachineWord(w, lex.GetAlphabet())
+		if err == nil && !lex.HasWord(mw) {
+			return true
+		}
+	}
+	return false
+}
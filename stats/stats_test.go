@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+func load(t *testing.T) (*pb.GameHistory, *game.GameRules) {
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h, rules
+}
+
+func TestCompute(t *testing.T) {
+	is := is.New(t)
+	h, rules := load(t)
+	report, err := Compute(h, rules)
+	is.NoErr(err)
+	is.Equal(len(report.Players), 2)
+
+	doug, emely := report.Players[0], report.Players[1]
+	is.Equal(doug.Nickname, "doug")
+	is.Equal(doug.Score, 451)
+	is.Equal(doug.Turns, 13)
+	is.Equal(doug.Plays, 13)
+	is.Equal(doug.Bingos, 2)
+	is.Equal(doug.BlanksPlayed, 1)
+	is.Equal(doug.SsPlayed, 2)
+	is.Equal(doug.ChallengesWon, 1)
+	is.Equal(*doug.HighestPlay, Play{Position: "10B", Tiles: "DONATES", Score: 82})
+	is.Equal(doug.AveragePerTurn, 451.0/13)
+
+	is.Equal(emely.Score, 345)
+	is.Equal(emely.Turns, 13)
+	is.Equal(emely.Plays, 12)
+	is.Equal(emely.PhoniesPlayed, 1)
+	is.Equal(emely.PhoniesStanding, 0)
+	is.Equal(emely.BlanksPlayed, 1)
+	is.Equal(emely.ChallengesLost, 0)
+
+	// Everything but doug's last rack of OPEG was played.
+	is.Equal(doug.TilesPlayed+emely.TilesPlayed, 96)
+	// 8D WINDY covers the double word at 8H.
+	is.True(doug.BonusSquares.DoubleWord >= 1)
+
+	// The report can be sent as is.
+	_, err = json.Marshal(report)
+	is.NoErr(err)
+	// The history is left alone.
+	is.Equal(h.ChallengeRule, pb.ChallengeRule_VOID)
+}
+
+func TestPhoniesStanding(t *testing.T) {
+	is := is.New(t)
+	h, rules := load(t)
+	// A lexicon with every word formed in the game except GALE.
+	words := []string{}
+	for _, evt := range h.Events {
+		for _, w := range evt.WordsFormed {
+			if w != "GALE" {
+				words = append(words, w)
+			}
+		}
+	}
+	lex, err := lexicon.NewWordList("MOST", rules.LetterDistribution().Alphabet(), words)
+	is.NoErr(err)
+	rules = game.NewGameRules(&DefaultConfig, rules.LetterDistribution(), rules.Board(),
+		lex, rules.CrossSetGen())
+
+	report, err := Compute(h, rules)
+	is.NoErr(err)
+	emely := report.Players[1]
+	is.Equal(emely.PhoniesPlayed, 2)
+	is.Equal(emely.PhoniesStanding, 1)
+	is.Equal(report.Players[0].PhoniesStanding, 0)
+}
@@ -0,0 +1,127 @@
+package winpct
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/move"
+	"github.com/domino14/cwgame/movegen"
+)
+
+// Autoplayer builds tables from games that a bot plays against itself,
+// always making the move with the highest equity.
+type Autoplayer struct {
+	gen   movegen.MoveGenerator
+	calc  equity.Calculator
+	rules *game.GameRules
+	// Threads is the number of games played at once.
+	Threads int
+	// MaxSpread is the MaxSpread of the tables built.
+	MaxSpread int
+}
+
+// NewAutoplayer creates an Autoplayer for games with the given rules.
+func NewAutoplayer(rules *game.GameRules, gen movegen.MoveGenerator, calc equity.Calculator) *Autoplayer {
+	return &Autoplayer{gen: gen, calc: calc, rules: rules,
+		Threads: runtime.NumCPU(), MaxSpread: DefaultMaxSpread}
+}
+
+// position is the player on turn, their spread and the tiles in the bag.
+type position struct {
+	player, spread, inBag int
+}
+
+// Generate plays the given number of games and counts every position in
+// them. If ctx is done, it returns the table built from the games that
+// were finished, along with the context's error.
+func (a *Autoplayer) Generate(ctx context.Context, games int) (*Table, error) {
+	t := NewTable(a.MaxSpread)
+	var mu sync.Mutex
+	var firstErr error
+	todo := make(chan struct{})
+	go func() {
+		defer close(todo)
+		for i := 0; i < games; i++ {
+			select {
+			case todo <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < a.Threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range todo {
+				played := NewTable(a.MaxSpread)
+				err := a.play(played)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				} else if err == nil {
+					t.Merge(played)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	log.Debug().Int("positions", t.Positions()).Msg("autoplay finished")
+	return t, ctx.Err()
+}
+
+// play plays one game, adding its positions to t.
+func (a *Autoplayer) play(t *Table) error {
+	g, err := game.NewGame(a.rules, []*pb.PlayerInfo{
+		{Nickname: "p1", UserId: "p1"},
+		{Nickname: "p2", UserId: "p2"},
+	})
+	if err != nil {
+		return err
+	}
+	g.StartGame()
+	positions := []position{}
+	for g.Playing() != pb.PlayState_GAME_OVER {
+		onturn := g.PlayerOnTurn()
+		inBag := g.Bag().TilesRemaining()
+		positions = append(positions, position{onturn, g.SpreadFor(onturn), inBag})
+		var m *move.Move
+		if g.Playing() == pb.PlayState_WAITING_FOR_FINAL_PASS {
+			m = move.NewPassMove(g.RackFor(onturn).TilesOn(), g.Alphabet())
+		} else {
+			moves := a.gen.GenerateMoves(g.Board(), g.RackFor(onturn), inBag)
+			equity.Rank(moves, a.calc, g.Board(), g.Bag(), nil)
+			m = moves[0]
+		}
+		if err := g.PlayMove(m, false, 0); err != nil {
+			return err
+		}
+	}
+	for _, p := range positions {
+		t.Add(p.spread, p.inBag, result(g.SpreadFor(p.player)))
+	}
+	return nil
+}
+
+// result is 1 for a win, 0.5 for a tie and 0 for a loss.
+func result(finalSpread int) float64 {
+	switch {
+	case finalSpread > 0:
+		return 1
+	case finalSpread == 0:
+		return 0.5
+	}
+	return 0
+}
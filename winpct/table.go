@@ -0,0 +1,155 @@
+package winpct
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+const (
+	// DefaultMaxSpread is the largest spread a new table tells apart.
+	DefaultMaxSpread = 300
+	// DefaultMinGames is the fewest games a lookup is based on, if the
+	// table has that many.
+	DefaultMinGames = 50
+)
+
+// Table holds how often the player on turn went on to win, by the number
+// of tiles in the bag and their spread. The spread is measured before the
+// player moves, so the advantage of being on turn is part of the table.
+type Table struct {
+	// MaxSpread is the largest spread the table tells apart; larger
+	// ones are counted as MaxSpread.
+	MaxSpread int `json:"maxSpread"`
+	// Games and Wins are indexed by tiles in the bag, then by spread
+	// plus MaxSpread. A tie counts as half a win.
+	Games [][]int     `json:"games"`
+	Wins  [][]float64 `json:"wins"`
+}
+
+// NewTable creates an empty table.
+func NewTable(maxSpread int) *Table {
+	return &Table{MaxSpread: maxSpread}
+}
+
+// LoadTable reads a table written by Write.
+func LoadTable(r io.Reader) (*Table, error) {
+	t := &Table{}
+	if err := json.NewDecoder(r).Decode(t); err != nil {
+		return nil, err
+	}
+	if t.MaxSpread <= 0 || len(t.Games) != len(t.Wins) {
+		return nil, fmt.Errorf("malformed win percentage table")
+	}
+	for i := range t.Games {
+		if len(t.Games[i]) != 2*t.MaxSpread+1 || len(t.Wins[i]) != len(t.Games[i]) {
+			return nil, fmt.Errorf("malformed win percentage table: row %d has the wrong size", i)
+		}
+	}
+	return t, nil
+}
+
+// LoadTableFile reads a table from a file.
+func LoadTableFile(filename string) (*Table, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadTable(f)
+}
+
+// Write writes the table as JSON.
+func (t *Table) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(t)
+}
+
+func (t *Table) column(spread int) int {
+	if spread > t.MaxSpread {
+		spread = t.MaxSpread
+	} else if spread < -t.MaxSpread {
+		spread = -t.MaxSpread
+	}
+	return spread + t.MaxSpread
+}
+
+// Add counts a position where the player on turn had the given spread
+// with inBag tiles in the bag, and won (1), tied (0.5) or lost (0).
+func (t *Table) Add(spread, inBag int, result float64) {
+	t.grow(inBag)
+	col := t.column(spread)
+	t.Games[inBag][col]++
+	t.Wins[inBag][col] += result
+}
+
+// Merge adds the counts of another table with the same MaxSpread.
+func (t *Table) Merge(o *Table) error {
+	if o.MaxSpread != t.MaxSpread {
+		return fmt.Errorf("cannot merge tables with spreads up to %d and %d",
+			t.MaxSpread, o.MaxSpread)
+	}
+	t.grow(len(o.Games) - 1)
+	for inBag := range o.Games {
+		for col := range o.Games[inBag] {
+			t.Games[inBag][col] += o.Games[inBag][col]
+			t.Wins[inBag][col] += o.Wins[inBag][col]
+		}
+	}
+	return nil
+}
+
+// grow makes sure the table has a row for inBag tiles in the bag.
+func (t *Table) grow(inBag int) {
+	for len(t.Games) <= inBag {
+		t.Games = append(t.Games, make([]int, 2*t.MaxSpread+1))
+		t.Wins = append(t.Wins, make([]float64, 2*t.MaxSpread+1))
+	}
+}
+
+// Positions is the number of positions counted in the table.
+func (t *Table) Positions() int {
+	n := 0
+	for _, row := range t.Games {
+		for _, g := range row {
+			n += g
+		}
+	}
+	return n
+}
+
+// WinPct returns the chance that the player on turn wins, given their
+// spread and the tiles in the bag, along with the number of positions
+// it is based on. Nearby spreads are pooled until at least minGames
+// positions are found. If the table has nothing for the number of
+// tiles in the bag, it returns a rough guess based on no positions.
+func (t *Table) WinPct(spread, inBag, minGames int) (float64, int) {
+	if inBag < len(t.Games) {
+		games, wins := 0, 0.0
+		col := t.column(spread)
+		row := t.Games[inBag]
+		for w := 0; w <= 2*t.MaxSpread; w++ {
+			if w == 0 {
+				games, wins = row[col], t.Wins[inBag][col]
+			} else {
+				for _, c := range []int{col - w, col + w} {
+					if c >= 0 && c < len(row) {
+						games += row[c]
+						wins += t.Wins[inBag][c]
+					}
+				}
+			}
+			if games >= minGames || (games > 0 && w == 2*t.MaxSpread) {
+				return wins / float64(games), games
+			}
+		}
+	}
+	return guess(spread, inBag), 0
+}
+
+// guess is a logistic curve that flattens as more tiles are left to play.
+func guess(spread, inBag int) float64 {
+	scale := 10 + 1.2*float64(inBag)
+	return 1 / (1 + math.Exp(-float64(spread)/scale))
+}
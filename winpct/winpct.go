@@ -0,0 +1,102 @@
+// Package winpct estimates the chance that a player wins a game from any
+// position, from a table of self-play games or by simulating.
+package winpct
+
+import (
+	"context"
+
+	"github.com/domino14/cwgame/game"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/montecarlo"
+	"github.com/domino14/cwgame/move"
+)
+
+// simPlies is enough plies for a simulation to reach the end of a game.
+const simPlies = 100
+
+// Estimator estimates win percentages from a table. If it has a Simmer,
+// it simulates the positions the table has too few games for instead.
+type Estimator struct {
+	table *Table
+	sim   *montecarlo.Simmer
+	// MinGames is the fewest positions a table estimate is based on
+	// before simulation is used instead.
+	MinGames int
+	// Stop says when a simulation stops.
+	Stop montecarlo.StopCondition
+}
+
+// NewEstimator creates an Estimator. Either of table and sim may be nil,
+// but not both.
+func NewEstimator(table *Table, sim *montecarlo.Simmer) *Estimator {
+	return &Estimator{table: table, sim: sim, MinGames: DefaultMinGames,
+		Stop: montecarlo.StopCondition{Iterations: 200}}
+}
+
+// Estimate returns the chance that player wins g, counting a tie as half
+// a win.
+func (e *Estimator) Estimate(ctx context.Context, g *game.Game, player int) (float64, error) {
+	if g.Playing() == pb.PlayState_GAME_OVER {
+		return result(g.SpreadFor(player)), nil
+	}
+	onturn := g.PlayerOnTurn()
+	games := 0
+	pct := guess(g.SpreadFor(onturn), g.Bag().TilesRemaining())
+	if e.table != nil {
+		pct, games = e.table.WinPct(g.SpreadFor(onturn), g.Bag().TilesRemaining(), e.MinGames)
+	}
+	if games < e.MinGames && e.sim != nil {
+		return e.Simulate(ctx, g, player)
+	}
+	if player != onturn {
+		pct = 1 - pct
+	}
+	return pct, nil
+}
+
+// Simulate estimates the chance that player wins g by playing it out to
+// the end many times. The player on turn makes the move with the
+// highest equity, and the rack of the player not on turn is unknown.
+func (e *Estimator) Simulate(ctx context.Context, g *game.Game, player int) (float64, error) {
+	if g.Playing() == pb.PlayState_GAME_OVER {
+		return result(g.SpreadFor(player)), nil
+	}
+	onturn := g.PlayerOnTurn()
+	var m *move.Move
+	if g.Playing() == pb.PlayState_WAITING_FOR_FINAL_PASS {
+		m = move.NewPassMove(g.RackFor(onturn).TilesOn(), g.Alphabet())
+	} else {
+		m = e.sim.Moves(g)[0]
+	}
+	sim := *e.sim
+	sim.Plies = simPlies
+	cands, err := sim.Simulate(ctx, g, []*move.Move{m}, e.Stop)
+	if err != nil {
+		return 0, err
+	}
+	pct := cands[0].Wins.Mean()
+	if player != onturn {
+		pct = 1 - pct
+	}
+	return pct, nil
+}
+
+// Delta returns how much m changes the chances of the player on turn,
+// which is positive if the move leaves them better off than the
+// estimate before it. g is not changed.
+func (e *Estimator) Delta(ctx context.Context, g *game.Game, m *move.Move) (float64, error) {
+	player := g.PlayerOnTurn()
+	before, err := e.Estimate(ctx, g, player)
+	if err != nil {
+		return 0, err
+	}
+	gc := g.Copy()
+	if err := gc.PlayMove(m, false, 0); err != nil {
+		return 0, err
+	}
+	after, err := e.Estimate(ctx, gc, player)
+	if err != nil {
+		return 0, err
+	}
+	return after - before, nil
+}
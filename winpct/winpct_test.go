@@ -0,0 +1,155 @@
+package winpct
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/domino14/cwgame/board"
+	"github.com/domino14/cwgame/config"
+	"github.com/domino14/cwgame/equity"
+	"github.com/domino14/cwgame/game"
+	"github.com/domino14/cwgame/gcgio"
+	pb "github.com/domino14/cwgame/gen/proto/cwgame"
+	"github.com/domino14/cwgame/lexicon"
+	"github.com/domino14/cwgame/montecarlo"
+	"github.com/domino14/cwgame/movegen"
+)
+
+var DefaultConfig = config.DefaultConfig()
+
+var words = []string{"AA", "AB", "AD", "AE", "AG", "AH", "AI", "AL", "AM", "AN",
+	"AR", "AS", "AT", "AW", "AX", "AY", "BE", "BI", "BO", "DE", "DO", "ED",
+	"EH", "EL", "EM", "EN", "ER", "ES", "EX", "GO", "HE", "HI", "HO", "ID",
+	"IN", "IS", "IT", "JO", "LA", "LI", "LO", "ME", "MI", "MO", "NE", "NO",
+	"OD", "OE", "OF", "OH", "OI", "ON", "OR", "OS", "OX", "PA", "PE", "PI",
+	"QI", "RE", "SO", "TA", "TI", "TO", "UN", "UP", "US", "UT", "WE", "XI",
+	"YA", "YE", "YO", "ZA", "ATE", "EAT", "TEA", "RAT", "TAR", "ART", "NET",
+	"TEN", "TON", "NOT", "SIT", "ITS", "TIE", "TOE", "ORE", "ROE", "RAN",
+	"SEA", "SET", "TOR", "ROT", "OAT", "LIT", "NIT", "TIN"}
+
+func setup(t *testing.T, turn int) (*game.Game, *montecarlo.Simmer, *Autoplayer) {
+	rules, err := game.NewBasicGameRules(&DefaultConfig, board.CrosswordGameBoard, "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := gcgio.ParseGCG(&DefaultConfig, "../gcgio/testdata/doug_v_emely.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := game.NewFromHistory(proto.Clone(h).(*pb.GameHistory), rules, turn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rules.LetterDistribution()
+	wl, err := lexicon.NewWordList("test", dist.Alphabet(), words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := movegen.NewTrieGenerator(wl, dist)
+	calc := equity.NewLeaveCalculator(dist)
+	sim := montecarlo.NewSimmer(gen, calc)
+	sim.Threads = 2
+	a := NewAutoplayer(rules, gen, calc)
+	a.Threads = 2
+	return g, sim, a
+}
+
+func TestTable(t *testing.T) {
+	is := is.New(t)
+	tb := NewTable(100)
+	for i := 0; i < 30; i++ {
+		tb.Add(10, 20, 1)
+		tb.Add(10, 20, 0.5)
+	}
+	for i := 0; i < 40; i++ {
+		tb.Add(-3, 20, 0)
+	}
+	// Spreads past the end of the table count as the largest one.
+	tb.Add(500, 20, 1)
+
+	pct, games := tb.WinPct(10, 20, 50)
+	is.Equal(games, 60)
+	is.Equal(pct, 0.75)
+
+	// Too few games at 5, so the spreads on either side are pooled.
+	pct, games = tb.WinPct(5, 20, 50)
+	is.Equal(games, 60)
+	pct, games = tb.WinPct(3, 20, 50)
+	is.Equal(games, 100)
+	is.Equal(pct, 45.0/100)
+
+	pct, games = tb.WinPct(1000, 20, 1)
+	is.Equal(games, 1)
+	is.Equal(pct, 1.0)
+
+	// Nothing with this many tiles in the bag.
+	pct, games = tb.WinPct(30, 50, 50)
+	is.Equal(games, 0)
+	is.True(pct > 0.5 && pct < 1)
+	is.Equal(guess(0, 50), 0.5)
+
+	var buf bytes.Buffer
+	is.NoErr(tb.Write(&buf))
+	loaded, err := LoadTable(&buf)
+	is.NoErr(err)
+	is.Equal(loaded, tb)
+	is.NoErr(loaded.Merge(tb))
+	is.Equal(loaded.Positions(), 2*tb.Positions())
+	is.True(loaded.Merge(NewTable(10)) != nil)
+
+	_, err = LoadTable(bytes.NewBufferString(`{"maxSpread": 2, "games": [[1, 2]], "wins": [[1, 2]]}`))
+	is.True(err != nil)
+}
+
+func TestGenerate(t *testing.T) {
+	is := is.New(t)
+	_, _, a := setup(t, 0)
+	tb, err := a.Generate(context.Background(), 4)
+	is.NoErr(err)
+	is.True(tb.Positions() > 4)
+	// Every game starts with a full bag less the two racks, and the
+	// second player may also see it if the first exchanges or passes.
+	pct, games := tb.WinPct(0, 86, 1)
+	is.True(games >= 4)
+	is.True(pct >= 0 && pct <= 1)
+}
+
+func TestEstimate(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	// doug leads 409 to 264, and emely is to play with one tile in the bag.
+	g, sim, _ := setup(t, 24)
+	is.Equal(g.SpreadFor(0), 145)
+	tb := NewTable(200)
+	for i := 0; i < 100; i++ {
+		tb.Add(-145, 1, float64(i%10/9))
+	}
+	e := NewEstimator(tb, nil)
+	emely, err := e.Estimate(ctx, g, 1)
+	is.NoErr(err)
+	doug, err := e.Estimate(ctx, g, 0)
+	is.NoErr(err)
+	is.Equal(emely, 0.1)
+	is.True(math.Abs(doug-0.9) < 1e-9)
+
+	// The table has nothing with the bag empty, so the position after
+	// emely's move is simulated.
+	moves := sim.Moves(g)
+	e = NewEstimator(tb, sim)
+	e.Stop = montecarlo.StopCondition{Iterations: 20}
+	delta, err := e.Delta(ctx, g, moves[0])
+	is.NoErr(err)
+	is.True(delta >= -0.1 && delta <= 0.9)
+	is.Equal(g.Turn(), 24)
+
+	// The game is over: doug won.
+	g, _, _ = setup(t, 28)
+	pct, err := NewEstimator(nil, sim).Simulate(ctx, g, 0)
+	is.NoErr(err)
+	is.Equal(pct, 1.0)
+}